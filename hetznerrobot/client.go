@@ -4,24 +4,39 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+const retryMinWait = time.Second
+
+// HetznerRobotClient is shared by all resources of a provider instance, so
+// its HTTP connections and per-endpoint rate limiters are shared as well.
 type HetznerRobotClient struct {
-	username string
-	password string
-	url      string
+	username     string
+	password     string
+	url          string
+	maxRetries   int
+	retryMaxWait time.Duration
+	httpClient   *http.Client
+	limiters     map[string]*tokenBucket
 }
 
-func NewHetznerRobotClient(username string, password string, url string) HetznerRobotClient {
-	return HetznerRobotClient{
-		username: username,
-		password: password,
-		url:      url,
+func NewHetznerRobotClient(username string, password string, url string, maxRetries int, retryMaxWait time.Duration) *HetznerRobotClient {
+	return &HetznerRobotClient{
+		username:     username,
+		password:     password,
+		url:          url,
+		maxRetries:   maxRetries,
+		retryMaxWait: retryMaxWait,
+		httpClient:   &http.Client{},
+		limiters:     newEndpointLimiters(),
 	}
 }
 
@@ -34,7 +49,57 @@ func codeIsInExpected(statusCode int, expectedStatusCodes []int) bool {
 	return false
 }
 
+// makeAPICall performs a Robot webservice request. Only GET requests are
+// retried on transient failures; use makeIdempotentAPICall for other methods
+// that are safe to repeat. Rate limited requests of any method are retried,
+// see isRetryable.
 func (c *HetznerRobotClient) makeAPICall(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int) ([]byte, error) {
+	return c.makeAPICallWithRetry(ctx, method, uri, data, expectedStatusCodes, method == http.MethodGet)
+}
+
+// makeIdempotentAPICall performs a Robot webservice request which leaves the
+// same result when repeated, e.g. replacing a firewall configuration, and thus
+// may be retried on transient failures.
+func (c *HetznerRobotClient) makeIdempotentAPICall(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int) ([]byte, error) {
+	return c.makeAPICallWithRetry(ctx, method, uri, data, expectedStatusCodes, true)
+}
+
+func (c *HetznerRobotClient) makeAPICallWithRetry(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int, idempotent bool) ([]byte, error) {
+	family := endpointFamily(c.url, uri)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.waitForEndpoint(ctx, family, uri); err != nil {
+			return nil, err
+		}
+
+		responseBytes, err := c.doAPICall(ctx, method, uri, data, expectedStatusCodes)
+		if err == nil {
+			return responseBytes, nil
+		}
+		if attempt >= c.maxRetries || !isRetryable(ctx, err, idempotent) {
+			return nil, err
+		}
+
+		wait := c.retryWait(attempt, family, err)
+		tflog.Warn(ctx, "retrying Hetzner webservice request", map[string]interface{}{
+			"uri":     uri,
+			"method":  method,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+			"error":   err.Error(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *HetznerRobotClient) doAPICall(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int) ([]byte, error) {
 	tflog.Debug(ctx, "requesting Hetzner webservice", map[string]interface{}{
 		"uri":    uri,
 		"method": method,
@@ -52,11 +117,9 @@ func (c *HetznerRobotClient) makeAPICall(ctx context.Context, method string, uri
 
 	request.SetBasicAuth(c.username, c.password)
 
-	client := http.Client{}
-
-	response, err := client.Do(request)
+	response, err := c.httpClient.Do(request)
 	if err != nil {
-		return nil, fmt.Errorf("error sending request: %v", err)
+		return nil, fmt.Errorf("error sending request: %w", err)
 	}
 
 	defer response.Body.Close()
@@ -72,8 +135,100 @@ func (c *HetznerRobotClient) makeAPICall(ctx context.Context, method string, uri
 	})
 
 	if !codeIsInExpected(response.StatusCode, expectedStatusCodes) {
-		return nil, fmt.Errorf("hetzner webservice response status %d: %s", response.StatusCode, responseBytes)
+		apiErr := newRobotAPIError(response.StatusCode, responseBytes)
+		if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, apiErr
 	}
 
 	return responseBytes, nil
 }
+
+// waitForEndpoint blocks until the endpoint family has budget left for another request.
+func (c *HetznerRobotClient) waitForEndpoint(ctx context.Context, family string, uri string) error {
+	limiter, ok := c.limiters[family]
+	if !ok {
+		return nil
+	}
+
+	delay := limiter.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	tflog.Info(ctx, "pacing Hetzner webservice request to stay within rate limit", map[string]interface{}{
+		"uri":      uri,
+		"endpoint": family,
+		"wait":     delay.String(),
+	})
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryable reports whether a failed request should be repeated. Rate limited
+// requests were never processed by Robot and are always retried, server errors
+// and network failures only when the request is idempotent.
+func isRetryable(ctx context.Context, err error, idempotent bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if IsRateLimited(err) {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+	apiErr, ok := asRobotAPIError(err)
+	if !ok {
+		return true
+	}
+	switch apiErr.HTTPStatus {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryWait returns an exponential backoff with jitter for the given attempt.
+// Rate limited requests wait at least as long as Robot takes to grant a new
+// request, based on the limit reported in the error or documented for the
+// endpoint family.
+func (c *HetznerRobotClient) retryWait(attempt int, family string, err error) time.Duration {
+	backoff := retryMinWait << uint(attempt)
+	wait := backoff/2 + jitter(backoff/2)
+
+	if IsRateLimited(err) {
+		limit := endpointLimit(family)
+		apiErr, _ := asRobotAPIError(err)
+		if apiErr.MaxRequest > 0 && apiErr.Interval > 0 {
+			limit = robotEndpointLimit{requests: apiErr.MaxRequest, per: time.Duration(apiErr.Interval) * time.Second}
+		}
+		floor := limit.interval()
+		if apiErr.RetryAfter > floor {
+			floor = apiErr.RetryAfter
+		}
+		if wait < floor {
+			wait = floor + jitter(floor/10)
+		}
+	}
+
+	if wait > c.retryMaxWait || wait <= 0 {
+		wait = c.retryMaxWait
+	}
+	return wait
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
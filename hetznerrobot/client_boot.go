@@ -7,16 +7,28 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/tidwall/gjson"
 )
 
+// Boot subsystems, named like their Robot endpoint /boot/{server-number}/{profile}.
+const (
+	bootProfileLinux   = "linux"
+	bootProfileRescue  = "rescue"
+	bootProfileVNC     = "vnc"
+	bootProfileWindows = "windows"
+	bootProfilePlesk   = "plesk"
+	bootProfileCPanel  = "cpanel"
+)
+
+var bootProfiles = []string{bootProfileLinux, bootProfileRescue, bootProfileVNC, bootProfileWindows, bootProfilePlesk, bootProfileCPanel}
+
 type BootProfile struct {
-	ActiveProfile   string // linux/rescue/...
+	ActiveProfile   string // linux/rescue/vnc/windows/plesk/cpanel
 	Architecture    string
-	AuthorizedKeys  []string
-	HostKeys        []string
+	AuthorizedKeys  []BootKey
+	HostKeys        []BootKey
+	Hostname        string
 	Language        string
 	OperatingSystem string
 	Password        string
@@ -25,84 +37,199 @@ type BootProfile struct {
 	ServerIPv6      string
 }
 
+// BootKey is an SSH key of a boot configuration, either one authorized for
+// login or a host key of the rescue system or installation.
+type BootKey struct {
+	Name        string
+	Fingerprint string
+	Type        string
+	Size        int
+}
+
 func (c *HetznerRobotClient) getBoot(ctx context.Context, serverID int) (*BootProfile, error) {
 	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/boot/%d", c.url, serverID), nil, []int{http.StatusOK, http.StatusAccepted})
 	if err != nil {
 		return nil, err
 	}
 
-	jsonStr := string(bytes)
-	bootProfile := BootProfile{}
-	activeBoot := ""
-
-	if gjson.Get(jsonStr, "boot.linux.active").Bool() {
-		activeBoot = gjson.Get(jsonStr, "boot.linux").String()
-		bootProfile.ActiveProfile = "linux"
-		bootProfile.Language = gjson.Get(activeBoot, "lang").String()
-		bootProfile.OperatingSystem = gjson.Get(activeBoot, "dist").String()
-	}
-	if gjson.Get(jsonStr, "boot.rescue.active").Bool() {
-		activeBoot = gjson.Get(jsonStr, "boot.rescue").String()
-		bootProfile.ActiveProfile = "rescue"
-		bootProfile.OperatingSystem = gjson.Get(activeBoot, "os").String()
-	}
-
-	bootProfile.Architecture = gjson.Get(activeBoot, "arch").String()
-	// bootProfile.AuthorizedKeys = gjson.Get(activeBoot, "authorised_keys").Array()
-	// bootProfile.HostKeys = gjson.Get(activeBoot, "host_keys").Array()
-	bootProfile.Password = gjson.Get(activeBoot, "password").String()
-	bootProfile.ServerID = int(gjson.Get(activeBoot, "server_num").Int())
-	bootProfile.ServerIPv4 = gjson.Get(activeBoot, "server_ip").String()
-	bootProfile.ServerIPv6 = gjson.Get(activeBoot, "server_ipv6_net").String()
-
-	return &bootProfile, nil
+	return parseBootProfile(string(bytes)), nil
 }
 
-func (c *HetznerRobotClient) setBootProfile(ctx context.Context, serverID int, activeBootProfile string, arch string, os string, lang string, authorizedKeys []string) (*BootProfile, error) {
+func (c *HetznerRobotClient) setBootProfile(ctx context.Context, serverID int, activeBootProfile string, arch string, os string, lang string, hostname string, authorizedKeys []string) (*BootProfile, error) {
 	data := url.Values{}
-	data.Set("arch", arch)
-	for _, key := range authorizedKeys {
-		data.Add("authorized_key", key)
+	if arch != "" {
+		data.Set("arch", arch)
 	}
-	if activeBootProfile == "linux" {
+	switch activeBootProfile {
+	case bootProfileLinux:
 		data.Set("dist", os)
 		data.Set("lang", lang)
-	}
-	if activeBootProfile == "rescue" {
+	case bootProfileRescue:
 		data.Set("os", os)
+	case bootProfileVNC:
+		data.Set("dist", os)
+		data.Set("lang", lang)
+	case bootProfileWindows:
+		if os != "" {
+			data.Set("dist", os)
+		}
+		data.Set("lang", lang)
+	case bootProfilePlesk, bootProfileCPanel:
+		data.Set("dist", os)
+		data.Set("lang", lang)
+		data.Set("hostname", hostname)
+	}
+	// only the linux installation and the rescue system take SSH keys
+	if activeBootProfile == bootProfileLinux || activeBootProfile == bootProfileRescue {
+		for _, key := range authorizedKeys {
+			data.Add("authorized_key", key)
+		}
 	}
 
-	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/boot/%d/%s", c.url, serverID, activeBootProfile), data, []int{http.StatusOK, http.StatusAccepted})
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/boot/%d/%s", c.url, serverID, activeBootProfile), data, []int{http.StatusOK, http.StatusAccepted})
 	if err != nil {
-		if strings.Contains(err.Error(), "BOOT_ALREADY_ENABLED") {
-			return c.getBoot(ctx, serverID)
+		// a retried activation finds the one it already made, a subsystem armed
+		// with other settings has to be deactivated first
+		if hasErrorCode(err, errorCodeBootAlreadyEnabled) {
+			current, getErr := c.getBoot(ctx, serverID)
+			if getErr == nil && bootProfileMatches(current, activeBootProfile, data) {
+				return current, nil
+			}
 		}
 		return nil, err
 	}
 
-	jsonStr := string(bytes)
+	return parseBootProfile(string(bytes)), nil
+}
+
+// bootProfileMatches reports whether current is profile armed with the
+// settings of the activation request data.
+func bootProfileMatches(current *BootProfile, profile string, data url.Values) bool {
+	if current.ActiveProfile != profile {
+		return false
+	}
+	for param, value := range map[string]string{
+		"arch":     current.Architecture,
+		"dist":     current.OperatingSystem,
+		"os":       current.OperatingSystem,
+		"lang":     current.Language,
+		"hostname": current.Hostname,
+	} {
+		if data.Has(param) && data.Get(param) != "" && data.Get(param) != value {
+			return false
+		}
+	}
+	if keys := data["authorized_key"]; len(keys) > 0 {
+		if len(keys) != len(current.AuthorizedKeys) {
+			return false
+		}
+		for _, key := range current.AuthorizedKeys {
+			if !stringInSlice(key.Fingerprint, keys) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseBootProfile decodes the active boot subsystem of a /boot response, or
+// of the response of activating a single subsystem.
+func parseBootProfile(jsonStr string) *BootProfile {
 	bootProfile := BootProfile{}
 	activeBoot := ""
 
-	if gjson.Get(jsonStr, "boot.linux.active").Bool() {
-		activeBoot = gjson.Get(jsonStr, "boot.linux").String()
-		bootProfile.ActiveProfile = "linux"
-		bootProfile.Language = gjson.Get(activeBoot, "lang").String()
-		bootProfile.OperatingSystem = gjson.Get(activeBoot, "dist").String()
-	}
-	if gjson.Get(jsonStr, "boot.rescue.active").Bool() {
-		activeBoot = gjson.Get(jsonStr, "boot.rescue").String()
-		bootProfile.ActiveProfile = "rescue"
-		bootProfile.OperatingSystem = gjson.Get(activeBoot, "os").String()
+	for _, profile := range bootProfiles {
+		// /boot wraps the subsystems in "boot", activating one returns it unwrapped
+		boot := gjson.Get(jsonStr, "boot."+profile)
+		if !boot.Exists() {
+			boot = gjson.Get(jsonStr, profile)
+		}
+		if !boot.Get("active").Bool() {
+			continue
+		}
+		activeBoot = boot.String()
+		bootProfile.ActiveProfile = profile
+		if profile == bootProfileRescue {
+			bootProfile.OperatingSystem = gjson.Get(activeBoot, "os").String()
+		} else {
+			bootProfile.Language = gjson.Get(activeBoot, "lang").String()
+			bootProfile.OperatingSystem = gjson.Get(activeBoot, "dist").String()
+		}
+		bootProfile.Hostname = gjson.Get(activeBoot, "hostname").String()
+		break
 	}
 
 	bootProfile.Architecture = gjson.Get(activeBoot, "arch").String()
-	// bootProfile.AuthorizedKeys = gjson.Get(activeBoot, "authorised_keys").Array()
-	// bootProfile.HostKeys = gjson.Get(activeBoot, "host_keys").Array()
+	bootProfile.AuthorizedKeys = parseBootKeys(gjson.Get(activeBoot, "authorized_key"))
+	bootProfile.HostKeys = parseBootKeys(gjson.Get(activeBoot, "host_key"))
 	bootProfile.Password = gjson.Get(activeBoot, "password").String()
-	bootProfile.ServerID = int(gjson.Get(activeBoot, "server_num").Int())
+	bootProfile.ServerID = int(gjson.Get(activeBoot, "server_number").Int())
 	bootProfile.ServerIPv4 = gjson.Get(activeBoot, "server_ip").String()
 	bootProfile.ServerIPv6 = gjson.Get(activeBoot, "server_ipv6_net").String()
 
-	return &bootProfile, nil
+	return &bootProfile
+}
+
+// BootOptions lists the values a boot subsystem accepts. While the subsystem
+// is active Robot only reports the values in use, Active is set then.
+type BootOptions struct {
+	Active       bool
+	Distribution []string
+	Architecture []string
+	Language     []string
+	OS           []string
+}
+
+func (c *HetznerRobotClient) getBootOptions(ctx context.Context, serverID int, profile string) (*BootOptions, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/boot/%d/%s", c.url, serverID, profile), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	options := gjson.Get(string(bytes), profile)
+	return &BootOptions{
+		Active:       options.Get("active").Bool(),
+		Distribution: bootOptionValues(options.Get("dist")),
+		Architecture: bootOptionValues(options.Get("arch")),
+		Language:     bootOptionValues(options.Get("lang")),
+		OS:           bootOptionValues(options.Get("os")),
+	}, nil
+}
+
+// bootOptionValues returns a list of option values, or the single value in use.
+func bootOptionValues(values gjson.Result) []string {
+	result := []string{}
+	if !values.Exists() || values.Type == gjson.Null {
+		return result
+	}
+	if !values.IsArray() {
+		return append(result, values.String())
+	}
+	for _, value := range values.Array() {
+		result = append(result, value.String())
+	}
+	return result
+}
+
+// parseBootKeys decodes a list of {"key": {...}} objects.
+func parseBootKeys(keys gjson.Result) []BootKey {
+	bootKeys := []BootKey{}
+	for _, key := range keys.Array() {
+		bootKeys = append(bootKeys, BootKey{
+			Name:        key.Get("key.name").String(),
+			Fingerprint: key.Get("key.fingerprint").String(),
+			Type:        key.Get("key.type").String(),
+			Size:        int(key.Get("key.size").Int()),
+		})
+	}
+	return bootKeys
+}
+
+// deleteBootProfile deactivates a boot subsystem.
+func (c *HetznerRobotClient) deleteBootProfile(ctx context.Context, serverID int, profile string) error {
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/boot/%d/%s", c.url, serverID, profile), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
 }
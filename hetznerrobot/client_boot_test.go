@@ -0,0 +1,114 @@
+package hetznerrobot
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestBootProfileMatches(t *testing.T) {
+	current := &BootProfile{
+		ActiveProfile:   bootProfileLinux,
+		OperatingSystem: "Debian 12 base",
+		Language:        "en",
+		AuthorizedKeys:  []BootKey{{Fingerprint: "aa:bb"}, {Fingerprint: "cc:dd"}},
+	}
+
+	cases := []struct {
+		name    string
+		profile string
+		data    url.Values
+		want    bool
+	}{
+		{"same settings", bootProfileLinux, url.Values{"dist": {"Debian 12 base"}, "lang": {"en"}, "authorized_key": {"cc:dd", "aa:bb"}}, true},
+		{"unset settings", bootProfileLinux, url.Values{"dist": {"Debian 12 base"}, "lang": {""}}, true},
+		{"other profile", bootProfileRescue, url.Values{"os": {"linux"}}, false},
+		{"other distribution", bootProfileLinux, url.Values{"dist": {"Ubuntu 24.04 base"}, "lang": {"en"}}, false},
+		{"other language", bootProfileLinux, url.Values{"dist": {"Debian 12 base"}, "lang": {"de"}}, false},
+		{"other keys", bootProfileLinux, url.Values{"dist": {"Debian 12 base"}, "authorized_key": {"aa:bb"}}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bootProfileMatches(current, tc.profile, tc.data); got != tc.want {
+				t.Errorf("bootProfileMatches() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// Payloads as returned by GET /boot/{server-number} and POST
+// /boot/{server-number}/rescue.
+const (
+	testBootInactive     = `{"boot":{"rescue":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":321,"os":["linux","vkvm"],"arch":[64],"active":false,"password":null,"authorized_key":[],"host_key":[],"boot_time":null},"linux":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":321,"dist":["Debian 12 base","Ubuntu 24.04 LTS base"],"arch":[64],"lang":["en"],"active":false,"password":null,"authorized_key":[],"host_key":[]},"vnc":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":321,"dist":["Fedora-40"],"arch":[64],"lang":["de_DE","en_US"],"active":false,"password":null},"windows":null,"plesk":null,"cpanel":null}}`
+	testBootLinuxActive  = `{"boot":{"rescue":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":321,"os":["linux","vkvm"],"arch":[64],"active":false,"password":null,"authorized_key":[],"host_key":[],"boot_time":null},"linux":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":321,"dist":"Debian 12 base","arch":64,"lang":"en","active":true,"password":null,"authorized_key":[{"key":{"name":"deploy","fingerprint":"56:29:99:a4:5d:ed:ac:95:c1:f5:88:82:90:5d:dd:10","type":"ED25519","size":256}}],"host_key":[]},"vnc":null,"windows":null,"plesk":null,"cpanel":null}}`
+	testRescueActivation = `{"rescue":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":321,"os":"linux","arch":64,"active":true,"password":"jEt0dtUvomJ5","authorized_key":[{"key":{"name":"deploy","fingerprint":"56:29:99:a4:5d:ed:ac:95:c1:f5:88:82:90:5d:dd:10","type":"ED25519","size":256}}],"host_key":[{"key":{"fingerprint":"c1:e4:08:73:dd:f7:e9:d1:94:ab:e9:0f:28:b2:d2:ed","type":"RSA","size":3072}},{"key":{"fingerprint":"9e:1c:42:0b:58:4f:26:d7:83:f3:aa:bb:0c:61:0d:7e","type":"ED25519","size":256}}],"boot_time":null}}`
+)
+
+func TestParseBootProfile(t *testing.T) {
+	deployKey := []BootKey{{Name: "deploy", Fingerprint: "56:29:99:a4:5d:ed:ac:95:c1:f5:88:82:90:5d:dd:10", Type: "ED25519", Size: 256}}
+
+	cases := []struct {
+		name string
+		json string
+		want BootProfile
+	}{
+		{"nothing active", testBootInactive, BootProfile{AuthorizedKeys: []BootKey{}, HostKeys: []BootKey{}}},
+		{"linux active", testBootLinuxActive, BootProfile{
+			ActiveProfile:   bootProfileLinux,
+			Architecture:    "64",
+			AuthorizedKeys:  deployKey,
+			HostKeys:        []BootKey{},
+			Language:        "en",
+			OperatingSystem: "Debian 12 base",
+			ServerID:        321,
+			ServerIPv4:      "192.0.2.1",
+			ServerIPv6:      "2001:db8:111:4221::",
+		}},
+		{"rescue activation", testRescueActivation, BootProfile{
+			ActiveProfile:  bootProfileRescue,
+			Architecture:   "64",
+			AuthorizedKeys: deployKey,
+			HostKeys: []BootKey{
+				{Fingerprint: "c1:e4:08:73:dd:f7:e9:d1:94:ab:e9:0f:28:b2:d2:ed", Type: "RSA", Size: 3072},
+				{Fingerprint: "9e:1c:42:0b:58:4f:26:d7:83:f3:aa:bb:0c:61:0d:7e", Type: "ED25519", Size: 256},
+			},
+			OperatingSystem: "linux",
+			Password:        "jEt0dtUvomJ5",
+			ServerID:        321,
+			ServerIPv4:      "192.0.2.1",
+			ServerIPv6:      "2001:db8:111:4221::",
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseBootProfile(tc.json); !reflect.DeepEqual(*got, tc.want) {
+				t.Errorf("parseBootProfile() = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseBootKeys(t *testing.T) {
+	cases := []struct {
+		name string
+		json string
+		want []BootKey
+	}{
+		{"missing", `{}`, []BootKey{}},
+		{"null", `{"host_key":null}`, []BootKey{}},
+		{"empty", `{"host_key":[]}`, []BootKey{}},
+		{"keys", `{"host_key":[{"key":{"fingerprint":"c1:e4:08:73:dd:f7:e9:d1:94:ab:e9:0f:28:b2:d2:ed","type":"DSA","size":1024}},{"key":{"name":"ops","fingerprint":"9e:1c:42:0b:58:4f:26:d7:83:f3:aa:bb:0c:61:0d:7e","type":"ECDSA","size":521}}]}`, []BootKey{
+			{Fingerprint: "c1:e4:08:73:dd:f7:e9:d1:94:ab:e9:0f:28:b2:d2:ed", Type: "DSA", Size: 1024},
+			{Name: "ops", Fingerprint: "9e:1c:42:0b:58:4f:26:d7:83:f3:aa:bb:0c:61:0d:7e", Type: "ECDSA", Size: 521},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseBootKeys(gjson.Get(tc.json, "host_key")); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseBootKeys() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	errorCodeBootAlreadyEnabled = "BOOT_ALREADY_ENABLED"
+	errorCodeConflict           = "CONFLICT"
+	errorCodeFailoverRouted     = "FAILOVER_ALREADY_ROUTED"
+	errorCodeInvalidInput       = "INVALID_INPUT"
+	errorCodeNotFound           = "NOT_FOUND"
+	errorCodeRateLimitExceeded  = "RATE_LIMIT_EXCEEDED"
+)
+
+type robotAPIErrorResponse struct {
+	Error RobotAPIError `json:"error"`
+}
+
+// RobotAPIError is the decoded error envelope of a failed Robot webservice call.
+type RobotAPIError struct {
+	HTTPStatus int           `json:"-"`
+	RetryAfter time.Duration `json:"-"`
+	Status     int           `json:"status"`
+	Code       string        `json:"code"`
+	Message    string        `json:"message"`
+	Missing    []string      `json:"missing"`
+	Invalid    []string      `json:"invalid"`
+	MaxRequest int           `json:"max_request"`
+	Interval   int           `json:"interval"`
+}
+
+func newRobotAPIError(httpStatus int, body []byte) *RobotAPIError {
+	response := robotAPIErrorResponse{}
+	if err := json.Unmarshal(body, &response); err != nil || response.Error.Code == "" {
+		return &RobotAPIError{
+			HTTPStatus: httpStatus,
+			Status:     httpStatus,
+			Message:    strings.TrimSpace(string(body)),
+		}
+	}
+
+	apiErr := response.Error
+	apiErr.HTTPStatus = httpStatus
+	if apiErr.Status == 0 {
+		apiErr.Status = httpStatus
+	}
+	return &apiErr
+}
+
+func (e *RobotAPIError) Error() string {
+	msg := fmt.Sprintf("hetzner webservice response status %d", e.Status)
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s %s", msg, e.Code)
+	}
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if len(e.Missing) > 0 {
+		msg = fmt.Sprintf("%s (missing: %s)", msg, strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		msg = fmt.Sprintf("%s (invalid: %s)", msg, strings.Join(e.Invalid, ", "))
+	}
+	return msg
+}
+
+// Fields returns the names of all input parameters Robot reported as missing or invalid.
+func (e *RobotAPIError) Fields() []string {
+	fields := make([]string, 0, len(e.Missing)+len(e.Invalid))
+	fields = append(fields, e.Missing...)
+	fields = append(fields, e.Invalid...)
+	return fields
+}
+
+func asRobotAPIError(err error) (*RobotAPIError, bool) {
+	var apiErr *RobotAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// hasErrorCode reports whether err is a RobotAPIError with the given code.
+func hasErrorCode(err error, code string) bool {
+	apiErr, ok := asRobotAPIError(err)
+	return ok && apiErr.Code == code
+}
+
+// IsNotFound reports whether err means the requested object does not exist,
+// e.g. SERVER_NOT_FOUND, KEY_NOT_FOUND or FIREWALL_NOT_FOUND. Only decoded Robot
+// error codes count, a bare 404 such as a proxy error page for a wrong url is
+// not taken as the object being gone.
+func IsNotFound(err error) bool {
+	apiErr, ok := asRobotAPIError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.Code == errorCodeNotFound ||
+		strings.HasSuffix(apiErr.Code, "_NOT_FOUND")
+}
+
+// IsConflict reports whether err means the request clashes with the current
+// state of the object, e.g. BOOT_ALREADY_ENABLED or KEY_ALREADY_EXISTS.
+func IsConflict(err error) bool {
+	apiErr, ok := asRobotAPIError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.Status == http.StatusConflict ||
+		apiErr.Code == errorCodeConflict ||
+		strings.Contains(apiErr.Code, "_ALREADY_")
+}
+
+// IsRateLimited reports whether err is Robot's RATE_LIMIT_EXCEEDED response.
+func IsRateLimited(err error) bool {
+	return hasErrorCode(err, errorCodeRateLimitExceeded)
+}
+
+// IsInProcess reports whether err means a previous change on the object is
+// still being applied, e.g. FIREWALL_IN_PROCESS or VSWITCH_IN_PROCESS.
+func IsInProcess(err error) bool {
+	apiErr, ok := asRobotAPIError(err)
+	return ok && strings.HasSuffix(apiErr.Code, "_IN_PROCESS")
+}
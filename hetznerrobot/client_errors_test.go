@@ -0,0 +1,77 @@
+package hetznerrobot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIsNotFound(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"robot not found", newRobotAPIError(http.StatusNotFound, []byte(`{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`)), true},
+		{"robot object not found", newRobotAPIError(http.StatusNotFound, []byte(`{"error":{"status":404,"code":"SERVER_NOT_FOUND","message":"Server not found"}}`)), true},
+		{"wrapped", fmt.Errorf("reading: %w", newRobotAPIError(http.StatusNotFound, []byte(`{"error":{"status":404,"code":"FIREWALL_NOT_FOUND"}}`))), true},
+		{"bare 404", newRobotAPIError(http.StatusNotFound, []byte("<html>404 page not found</html>")), false},
+		{"other code", newRobotAPIError(http.StatusConflict, []byte(`{"error":{"status":409,"code":"KEY_ALREADY_EXISTS"}}`)), false},
+		{"not an api error", errors.New("connection refused"), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsNotFound(tc.err); got != tc.want {
+				t.Errorf("IsNotFound() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewRobotAPIError(t *testing.T) {
+	t.Run("envelope", func(t *testing.T) {
+		apiErr := newRobotAPIError(http.StatusBadRequest, []byte(`{"error":{"status":400,"code":"INVALID_INPUT","message":"invalid input","missing":["name"],"invalid":["src_ip","dst_port"]}}`))
+		if apiErr.HTTPStatus != http.StatusBadRequest || apiErr.Status != 400 || apiErr.Code != errorCodeInvalidInput || apiErr.Message != "invalid input" {
+			t.Fatalf("unexpected decoded error %#v", apiErr)
+		}
+		if fields := apiErr.Fields(); len(fields) != 3 || fields[0] != "name" || fields[1] != "src_ip" || fields[2] != "dst_port" {
+			t.Errorf("Fields() = %v", fields)
+		}
+		want := "hetzner webservice response status 400 INVALID_INPUT: invalid input (missing: name) (invalid: src_ip, dst_port)"
+		if apiErr.Error() != want {
+			t.Errorf("Error() = %q, want %q", apiErr.Error(), want)
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		apiErr := newRobotAPIError(http.StatusForbidden, []byte(`{"error":{"status":403,"code":"RATE_LIMIT_EXCEEDED","message":"Rate limit exceeded","max_request":50,"interval":3600}}`))
+		if !IsRateLimited(apiErr) || apiErr.MaxRequest != 50 || apiErr.Interval != 3600 {
+			t.Errorf("unexpected decoded error %#v", apiErr)
+		}
+	})
+
+	t.Run("envelope without status", func(t *testing.T) {
+		apiErr := newRobotAPIError(http.StatusConflict, []byte(`{"error":{"code":"KEY_ALREADY_EXISTS"}}`))
+		if apiErr.Status != http.StatusConflict || !IsConflict(apiErr) {
+			t.Errorf("unexpected decoded error %#v", apiErr)
+		}
+	})
+
+	t.Run("non-JSON body", func(t *testing.T) {
+		apiErr := newRobotAPIError(http.StatusBadGateway, []byte("  <html>bad gateway</html>\n"))
+		if apiErr.HTTPStatus != http.StatusBadGateway || apiErr.Status != http.StatusBadGateway || apiErr.Code != "" {
+			t.Fatalf("unexpected decoded error %#v", apiErr)
+		}
+		if apiErr.Message != "<html>bad gateway</html>" {
+			t.Errorf("Message = %q", apiErr.Message)
+		}
+	})
+
+	t.Run("JSON without envelope", func(t *testing.T) {
+		apiErr := newRobotAPIError(http.StatusInternalServerError, []byte(`{"message":"oops"}`))
+		if apiErr.Code != "" || apiErr.Message != `{"message":"oops"}` {
+			t.Errorf("unexpected decoded error %#v", apiErr)
+		}
+	})
+}
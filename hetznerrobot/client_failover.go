@@ -0,0 +1,87 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#failover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type HetznerRobotFailoverResponse struct {
+	Failover HetznerRobotFailover `json:"failover"`
+}
+
+type HetznerRobotFailover struct {
+	IP             string `json:"ip"`
+	Netmask        string `json:"netmask"`
+	ServerIP       string `json:"server_ip"`
+	ServerIPv6     string `json:"server_ipv6_net"`
+	ServerNumber   int    `json:"server_number"`
+	ActiveServerIP string `json:"active_server_ip"`
+}
+
+func (c *HetznerRobotClient) getFailovers(ctx context.Context) ([]HetznerRobotFailover, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/failover", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if IsNotFound(err) {
+			return []HetznerRobotFailover{}, nil
+		}
+		return nil, err
+	}
+
+	failoverResponses := []HetznerRobotFailoverResponse{}
+	if err = json.Unmarshal(bytes, &failoverResponses); err != nil {
+		return nil, err
+	}
+
+	failovers := make([]HetznerRobotFailover, len(failoverResponses))
+	for i, failoverResponse := range failoverResponses {
+		failovers[i] = failoverResponse.Failover
+	}
+	return failovers, nil
+}
+
+func (c *HetznerRobotClient) getFailover(ctx context.Context, ip string) (*HetznerRobotFailover, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/failover/%s", c.url, ip), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	failover := HetznerRobotFailoverResponse{}
+	if err = json.Unmarshal(bytes, &failover); err != nil {
+		return nil, err
+	}
+	return &failover.Failover, nil
+}
+
+// routeFailover routes the failover ip to activeServerIP. Routing it to the
+// server it is already routed to is not an error.
+func (c *HetznerRobotClient) routeFailover(ctx context.Context, ip string, activeServerIP string) (*HetznerRobotFailover, error) {
+	data := url.Values{}
+	data.Set("active_server_ip", activeServerIP)
+
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/failover/%s", c.url, ip), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if hasErrorCode(err, errorCodeFailoverRouted) {
+			return c.getFailover(ctx, ip)
+		}
+		return nil, err
+	}
+
+	failover := HetznerRobotFailoverResponse{}
+	if err = json.Unmarshal(bytes, &failover); err != nil {
+		return nil, err
+	}
+	return &failover.Failover, nil
+}
+
+func (c *HetznerRobotClient) unrouteFailover(ctx context.Context, ip string) error {
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/failover/%s", c.url, ip), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
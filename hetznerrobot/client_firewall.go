@@ -6,6 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+)
+
+const (
+	firewallStatusActive    = "active"
+	firewallStatusDisabled  = "disabled"
+	firewallStatusInProcess = "in process"
 )
 
 type HetznerRobotFirewallResponse struct {
@@ -15,23 +22,27 @@ type HetznerRobotFirewallResponse struct {
 type HetznerRobotFirewall struct {
 	IP                       string                    `json:"server_ip"`
 	WhitelistHetznerServices bool                      `json:"whitelist_hos"`
+	FilterIPv6               bool                      `json:"filter_ipv6"`
 	Status                   string                    `json:"status"`
+	TemplateID               int                       `json:"-"`
 	Rules                    HetznerRobotFirewallRules `json:"rules"`
 }
 
 type HetznerRobotFirewallRules struct {
-	Input []HetznerRobotFirewallRule `json:"input"`
+	Input  []HetznerRobotFirewallRule `json:"input"`
+	Output []HetznerRobotFirewallRule `json:"output"`
 }
 
 type HetznerRobotFirewallRule struct {
-	Name     string `json:"name"`
-	DstIP    string `json:"dst_ip"`
-	DstPort  string `json:"dst_port"`
-	SrcIP    string `json:"src_ip"`
-	SrcPort  string `json:"src_port"`
-	Protocol string `json:"protocol"`
-	TCPFlags string `json:"tcp_flags"`
-	Action   string `json:"action"`
+	Name      string `json:"name"`
+	IPVersion string `json:"ip_version"`
+	DstIP     string `json:"dst_ip"`
+	DstPort   string `json:"dst_port"`
+	SrcIP     string `json:"src_ip"`
+	SrcPort   string `json:"src_port"`
+	Protocol  string `json:"protocol"`
+	TCPFlags  string `json:"tcp_flags"`
+	Action    string `json:"action"`
 }
 
 func (c *HetznerRobotClient) getFirewall(ctx context.Context, ip string) (*HetznerRobotFirewall, error) {
@@ -51,44 +62,56 @@ func (c *HetznerRobotClient) getFirewall(ctx context.Context, ip string) (*Hetzn
 func (c *HetznerRobotClient) setFirewall(ctx context.Context, firewall HetznerRobotFirewall) error {
 	data := url.Values{}
 
-	whitelistHOS := "false"
-	if firewall.WhitelistHetznerServices {
-		whitelistHOS = "true"
-	}
-
-	data.Set("whitelist_hos", whitelistHOS)
 	data.Set("status", firewall.Status)
+	if firewall.TemplateID != 0 {
+		data.Set("template_id", strconv.Itoa(firewall.TemplateID))
+	} else {
+		data.Set("whitelist_hos", strconv.FormatBool(firewall.WhitelistHetznerServices))
+		data.Set("filter_ipv6", strconv.FormatBool(firewall.FilterIPv6))
 
-	for idx, rule := range firewall.Rules.Input {
-		data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "ip_version"), "ipv4")
-		if rule.Name != "" {
-			data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "name"), rule.Name)
-		}
-		if rule.DstIP != "" {
-			data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "dst_ip"), rule.DstIP)
-		}
-		if rule.DstPort != "" {
-			data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "dst_port"), rule.DstPort)
-		}
-		if rule.SrcIP != "" {
-			data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "src_ip"), rule.SrcIP)
-		}
-		if rule.SrcPort != "" {
-			data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "src_port"), rule.SrcPort)
-		}
-		if rule.Protocol != "" {
-			data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "protocol"), rule.Protocol)
-		}
-		if rule.TCPFlags != "" {
-			data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "tcp_flags"), rule.TCPFlags)
-		}
-		data.Set(fmt.Sprintf("rules[input][%d][%s]", idx, "action"), rule.Action)
+		encodeFirewallRules(data, "input", firewall.Rules.Input)
+		encodeFirewallRules(data, "output", firewall.Rules.Output)
 	}
 
-	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/%s", c.url, firewall.IP), data, []int{http.StatusOK, http.StatusAccepted})
+	_, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/%s", c.url, firewall.IP), data, []int{http.StatusOK, http.StatusAccepted})
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (c *HetznerRobotClient) deleteFirewall(ctx context.Context, ip string) error {
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/firewall/%s", c.url, ip), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// encodeFirewallRules adds the rules of one direction (input/output) as
+// rules[direction][idx][field] form parameters.
+func encodeFirewallRules(data url.Values, direction string, rules []HetznerRobotFirewallRule) {
+	for idx, rule := range rules {
+		fields := []struct {
+			name  string
+			value string
+		}{
+			{"ip_version", rule.IPVersion},
+			{"name", rule.Name},
+			{"dst_ip", rule.DstIP},
+			{"dst_port", rule.DstPort},
+			{"src_ip", rule.SrcIP},
+			{"src_port", rule.SrcPort},
+			{"protocol", rule.Protocol},
+			{"tcp_flags", rule.TCPFlags},
+		}
+		for _, field := range fields {
+			if field.value != "" {
+				data.Set(fmt.Sprintf("rules[%s][%d][%s]", direction, idx, field.name), field.value)
+			}
+		}
+		data.Set(fmt.Sprintf("rules[%s][%d][%s]", direction, idx, "action"), rule.Action)
+	}
+}
@@ -0,0 +1,106 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#firewall-templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type HetznerRobotFirewallTemplateResponse struct {
+	FirewallTemplate HetznerRobotFirewallTemplate `json:"firewall_template"`
+}
+
+type HetznerRobotFirewallTemplate struct {
+	ID                       int                       `json:"id"`
+	Name                     string                    `json:"name"`
+	IsDefault                bool                      `json:"is_default"`
+	WhitelistHetznerServices bool                      `json:"whitelist_hos"`
+	FilterIPv6               bool                      `json:"filter_ipv6"`
+	Rules                    HetznerRobotFirewallRules `json:"rules"`
+}
+
+func (c *HetznerRobotClient) getFirewallTemplates(ctx context.Context) ([]HetznerRobotFirewallTemplate, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/firewall/template", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if IsNotFound(err) {
+			return []HetznerRobotFirewallTemplate{}, nil
+		}
+		return nil, err
+	}
+
+	templateResponses := []HetznerRobotFirewallTemplateResponse{}
+	if err = json.Unmarshal(bytes, &templateResponses); err != nil {
+		return nil, err
+	}
+
+	templates := make([]HetznerRobotFirewallTemplate, len(templateResponses))
+	for i, templateResponse := range templateResponses {
+		templates[i] = templateResponse.FirewallTemplate
+	}
+	return templates, nil
+}
+
+func (c *HetznerRobotClient) getFirewallTemplate(ctx context.Context, id string) (*HetznerRobotFirewallTemplate, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/firewall/template/%s", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	template := HetznerRobotFirewallTemplateResponse{}
+	if err = json.Unmarshal(bytes, &template); err != nil {
+		return nil, err
+	}
+	return &template.FirewallTemplate, nil
+}
+
+func (c *HetznerRobotClient) createFirewallTemplate(ctx context.Context, template HetznerRobotFirewallTemplate) (*HetznerRobotFirewallTemplate, error) {
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/template", c.url), encodeFirewallTemplate(template), []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	created := HetznerRobotFirewallTemplateResponse{}
+	if err = json.Unmarshal(bytes, &created); err != nil {
+		return nil, err
+	}
+	return &created.FirewallTemplate, nil
+}
+
+func (c *HetznerRobotClient) updateFirewallTemplate(ctx context.Context, id string, template HetznerRobotFirewallTemplate) (*HetznerRobotFirewallTemplate, error) {
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/template/%s", c.url, id), encodeFirewallTemplate(template), []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	updated := HetznerRobotFirewallTemplateResponse{}
+	if err = json.Unmarshal(bytes, &updated); err != nil {
+		return nil, err
+	}
+	return &updated.FirewallTemplate, nil
+}
+
+func (c *HetznerRobotClient) deleteFirewallTemplate(ctx context.Context, id string) error {
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/firewall/template/%s", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func encodeFirewallTemplate(template HetznerRobotFirewallTemplate) url.Values {
+	data := url.Values{}
+	data.Set("name", template.Name)
+	data.Set("is_default", strconv.FormatBool(template.IsDefault))
+	data.Set("whitelist_hos", strconv.FormatBool(template.WhitelistHetznerServices))
+	data.Set("filter_ipv6", strconv.FormatBool(template.FilterIPv6))
+
+	encodeFirewallRules(data, "input", template.Rules.Input)
+	encodeFirewallRules(data, "output", template.Rules.Output)
+
+	return data
+}
@@ -0,0 +1,100 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#ip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type HetznerRobotIPResponse struct {
+	IP HetznerRobotIP `json:"ip"`
+}
+
+type HetznerRobotIP struct {
+	IP           string `json:"ip"`
+	ServerIP     string `json:"server_ip"`
+	ServerNumber int    `json:"server_number"`
+	Locked       bool   `json:"locked"`
+	SeparateMac  string `json:"separate_mac"`
+	HetznerRobotTrafficWarnings
+}
+
+// HetznerRobotTrafficWarnings holds the traffic warning settings of an IP or subnet.
+type HetznerRobotTrafficWarnings struct {
+	TrafficWarnings bool `json:"traffic_warnings"`
+	TrafficHourly   int  `json:"traffic_hourly"`
+	TrafficDaily    int  `json:"traffic_daily"`
+	TrafficMonthly  int  `json:"traffic_monthly"`
+}
+
+type HetznerRobotIPMacResponse struct {
+	Mac HetznerRobotIPMac `json:"mac"`
+}
+
+type HetznerRobotIPMac struct {
+	IP  string `json:"ip"`
+	Mac string `json:"mac"`
+}
+
+func (c *HetznerRobotClient) getIP(ctx context.Context, ip string) (*HetznerRobotIP, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/ip/%s", c.url, ip), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	ipResponse := HetznerRobotIPResponse{}
+	if err = json.Unmarshal(bytes, &ipResponse); err != nil {
+		return nil, err
+	}
+	return &ipResponse.IP, nil
+}
+
+func (c *HetznerRobotClient) setIPTrafficWarnings(ctx context.Context, ip string, warnings HetznerRobotTrafficWarnings) (*HetznerRobotIP, error) {
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/ip/%s", c.url, ip), encodeTrafficWarnings(warnings), []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	ipResponse := HetznerRobotIPResponse{}
+	if err = json.Unmarshal(bytes, &ipResponse); err != nil {
+		return nil, err
+	}
+	return &ipResponse.IP, nil
+}
+
+// createIPMac generates a separate MAC address for ip. It is not retried on
+// transient failures, a repeated PUT after a successful one conflicts.
+func (c *HetznerRobotClient) createIPMac(ctx context.Context, ip string) (*HetznerRobotIPMac, error) {
+	bytes, err := c.makeAPICall(ctx, "PUT", fmt.Sprintf("%s/ip/%s/mac", c.url, ip), nil, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	mac := HetznerRobotIPMacResponse{}
+	if err = json.Unmarshal(bytes, &mac); err != nil {
+		return nil, err
+	}
+	return &mac.Mac, nil
+}
+
+func (c *HetznerRobotClient) deleteIPMac(ctx context.Context, ip string) error {
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/ip/%s/mac", c.url, ip), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func encodeTrafficWarnings(warnings HetznerRobotTrafficWarnings) url.Values {
+	data := url.Values{}
+	data.Set("traffic_warnings", strconv.FormatBool(warnings.TrafficWarnings))
+	data.Set("traffic_hourly", strconv.Itoa(warnings.TrafficHourly))
+	data.Set("traffic_daily", strconv.Itoa(warnings.TrafficDaily))
+	data.Set("traffic_monthly", strconv.Itoa(warnings.TrafficMonthly))
+	return data
+}
@@ -0,0 +1,102 @@
+package hetznerrobot
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotEndpointLimit is the request budget Robot documents for an endpoint family.
+type robotEndpointLimit struct {
+	requests int
+	per      time.Duration
+}
+
+// interval is the time it takes Robot to grant one more request of the budget.
+func (l robotEndpointLimit) interval() time.Duration {
+	return l.per / time.Duration(l.requests)
+}
+
+const defaultEndpointFamily = "default"
+
+// robotEndpointLimits holds the most restrictive documented limit per endpoint family,
+// see https://robot.your-server.de/doc/webservice/en.html
+var robotEndpointLimits = map[string]robotEndpointLimit{
+	"boot":                {requests: 500, per: time.Hour},
+	"firewall":            {requests: 500, per: time.Hour},
+	"key":                 {requests: 500, per: time.Hour},
+	"reset":               {requests: 50, per: time.Hour},
+	"server":              {requests: 200, per: time.Hour},
+	"vswitch":             {requests: 100, per: time.Hour},
+	"wol":                 {requests: 500, per: time.Hour},
+	defaultEndpointFamily: {requests: 200, per: time.Hour},
+}
+
+// endpointFamily returns the first path segment of a Robot webservice URI,
+// e.g. "boot" for https://robot-ws.your-server.de/boot/123/rescue.
+func endpointFamily(baseURL string, uri string) string {
+	path := strings.TrimPrefix(uri, baseURL)
+	if parsed, err := url.Parse(path); err == nil {
+		path = parsed.Path
+	}
+	family := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	if _, ok := robotEndpointLimits[family]; !ok {
+		return defaultEndpointFamily
+	}
+	return family
+}
+
+func endpointLimit(family string) robotEndpointLimit {
+	if limit, ok := robotEndpointLimits[family]; ok {
+		return limit
+	}
+	return robotEndpointLimits[defaultEndpointFamily]
+}
+
+// tokenBucket paces requests of one endpoint family so that a provider instance
+// stays within the Robot budget. It starts full and refills continuously.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newTokenBucket(limit robotEndpointLimit) *tokenBucket {
+	return &tokenBucket{
+		tokens:   float64(limit.requests),
+		capacity: float64(limit.requests),
+		rate:     float64(limit.requests) / limit.per.Seconds(),
+		last:     time.Now(),
+	}
+}
+
+// reserve takes one token and returns how long the caller has to wait before
+// the token may be used.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func newEndpointLimiters() map[string]*tokenBucket {
+	limiters := make(map[string]*tokenBucket, len(robotEndpointLimits))
+	for family, limit := range robotEndpointLimits {
+		limiters[family] = newTokenBucket(limit)
+	}
+	return limiters
+}
@@ -0,0 +1,67 @@
+package hetznerrobot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEndpointFamily(t *testing.T) {
+	cases := []struct {
+		name    string
+		baseURL string
+		uri     string
+		want    string
+	}{
+		{"boot", "https://robot-ws.your-server.de", "https://robot-ws.your-server.de/boot/123/rescue", "boot"},
+		{"collection", "https://robot-ws.your-server.de", "https://robot-ws.your-server.de/key", "key"},
+		{"prefixed base url", "https://proxy.example.com/robot", "https://proxy.example.com/robot/firewall/123", "firewall"},
+		{"query string", "https://robot-ws.your-server.de", "https://robot-ws.your-server.de/server?server_ip=1.2.3.4", "server"},
+		{"query string on family", "https://robot-ws.your-server.de", "https://robot-ws.your-server.de/reset?foo=bar", "reset"},
+		{"unknown family", "https://robot-ws.your-server.de", "https://robot-ws.your-server.de/rdns/1.2.3.4", defaultEndpointFamily},
+		{"root", "https://robot-ws.your-server.de", "https://robot-ws.your-server.de/", defaultEndpointFamily},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := endpointFamily(tc.baseURL, tc.uri); got != tc.want {
+				t.Errorf("endpointFamily(%q, %q) = %q, want %q", tc.baseURL, tc.uri, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEndpointLimitFallback(t *testing.T) {
+	if got, want := endpointLimit("unknown"), robotEndpointLimits[defaultEndpointFamily]; got != want {
+		t.Errorf("endpointLimit(unknown) = %v, want default %v", got, want)
+	}
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	// two requests per two seconds, i.e. one more request every second once empty
+	bucket := newTokenBucket(robotEndpointLimit{requests: 2, per: 2 * time.Second})
+
+	const tolerance = 50 * time.Millisecond
+	want := []time.Duration{0, 0, time.Second, 2 * time.Second, 3 * time.Second}
+	for i, expected := range want {
+		got := bucket.reserve()
+		if got > expected || got < expected-tolerance {
+			t.Errorf("reservation %d: wait %s, want %s", i+1, got, expected)
+		}
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	bucket := newTokenBucket(robotEndpointLimit{requests: 2, per: 2 * time.Second})
+	bucket.reserve()
+	bucket.reserve()
+
+	// pretend the bucket was emptied long ago, it refills up to its capacity only
+	bucket.last = bucket.last.Add(-time.Hour)
+	for i := 0; i < 2; i++ {
+		if wait := bucket.reserve(); wait != 0 {
+			t.Errorf("reservation %d after refill: wait %s, want none", i+1, wait)
+		}
+	}
+	if wait := bucket.reserve(); wait <= 0 {
+		t.Errorf("reservation beyond capacity did not wait")
+	}
+}
@@ -0,0 +1,330 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#server-ordering
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	transactionStatusReady     = "ready"
+	transactionStatusInProcess = "in process"
+	transactionStatusCancelled = "cancelled"
+)
+
+type HetznerRobotServerProductResponse struct {
+	Product HetznerRobotServerProduct `json:"product"`
+}
+
+type HetznerRobotServerProduct struct {
+	ID              string                           `json:"id"`
+	Name            string                           `json:"name"`
+	Description     []string                         `json:"description"`
+	Traffic         string                           `json:"traffic"`
+	Dist            []string                         `json:"dist"`
+	Lang            []string                         `json:"lang"`
+	Location        []string                         `json:"location"`
+	Prices          []HetznerRobotProductPrice       `json:"prices"`
+	OrderableAddons []HetznerRobotServerProductAddon `json:"orderable_addons"`
+}
+
+type HetznerRobotProductPrice struct {
+	Location   string            `json:"location"`
+	Price      HetznerRobotPrice `json:"price"`
+	PriceSetup HetznerRobotPrice `json:"price_setup"`
+}
+
+// HetznerRobotPrice holds a price in EUR, Robot sends amounts as strings.
+type HetznerRobotPrice struct {
+	Net   string `json:"net"`
+	Gross string `json:"gross"`
+}
+
+type HetznerRobotServerProductAddon struct {
+	ID     string                     `json:"id"`
+	Name   string                     `json:"name"`
+	Min    int                        `json:"min"`
+	Max    int                        `json:"max"`
+	Prices []HetznerRobotProductPrice `json:"prices"`
+}
+
+type HetznerRobotServerMarketProductResponse struct {
+	Product HetznerRobotServerMarketProduct `json:"product"`
+}
+
+// HetznerRobotServerMarketProduct is a server offered in the server auction,
+// sizes are in GB and prices in EUR.
+type HetznerRobotServerMarketProduct struct {
+	ID              int                              `json:"id"`
+	Name            string                           `json:"name"`
+	Description     []string                         `json:"description"`
+	Traffic         string                           `json:"traffic"`
+	Dist            []string                         `json:"dist"`
+	Lang            []string                         `json:"lang"`
+	CPU             string                           `json:"cpu"`
+	CPUBenchmark    int                              `json:"cpu_benchmark"`
+	MemorySize      float64                          `json:"memory_size"`
+	HddSize         float64                          `json:"hdd_size"`
+	HddText         string                           `json:"hdd_text"`
+	HddCount        int                              `json:"hdd_count"`
+	Datacenter      string                           `json:"datacenter"`
+	NetworkSpeed    string                           `json:"network_speed"`
+	Price           string                           `json:"price"`
+	PriceHourly     string                           `json:"price_hourly"`
+	PriceSetup      string                           `json:"price_setup"`
+	FixedPrice      bool                             `json:"fixed_price"`
+	NextReduce      int                              `json:"next_reduce"`
+	NextReduceDate  string                           `json:"next_reduce_date"`
+	OrderableAddons []HetznerRobotServerProductAddon `json:"orderable_addons"`
+}
+
+type HetznerRobotServerTransactionResponse struct {
+	Transaction HetznerRobotServerTransaction `json:"transaction"`
+}
+
+type HetznerRobotServerTransaction struct {
+	ID           string `json:"id"`
+	Date         string `json:"date"`
+	Status       string `json:"status"`
+	ServerNumber int    `json:"server_number"`
+	ServerIP     string `json:"server_ip"`
+	Comment      string `json:"comment"`
+}
+
+type HetznerRobotServerOrder struct {
+	ProductID      string
+	Dist           string
+	Arch           int
+	Lang           string
+	Location       string
+	AuthorizedKeys []string
+	Addons         []string
+	Comment        string
+	Test           bool
+}
+
+// netAmount returns the net amount of price, or 0 if Robot sent none.
+func (p HetznerRobotPrice) netAmount() float64 {
+	amount, _ := strconv.ParseFloat(p.Net, 64)
+	return amount
+}
+
+func (c *HetznerRobotClient) getServerProducts(ctx context.Context) ([]HetznerRobotServerProduct, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/order/server/product", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if IsNotFound(err) {
+			return []HetznerRobotServerProduct{}, nil
+		}
+		return nil, err
+	}
+
+	productResponses := []HetznerRobotServerProductResponse{}
+	if err = json.Unmarshal(bytes, &productResponses); err != nil {
+		return nil, err
+	}
+
+	products := make([]HetznerRobotServerProduct, len(productResponses))
+	for i, productResponse := range productResponses {
+		products[i] = productResponse.Product
+	}
+	return products, nil
+}
+
+func (c *HetznerRobotClient) getServerTransaction(ctx context.Context, id string) (*HetznerRobotServerTransaction, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/order/server/transaction/%s", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := HetznerRobotServerTransactionResponse{}
+	if err = json.Unmarshal(bytes, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction.Transaction, nil
+}
+
+// orderServer places a server order. Only rate limited attempts are retried,
+// Robot rejected those unprocessed; a retried order that got through could buy
+// the server twice.
+func (c *HetznerRobotClient) orderServer(ctx context.Context, order HetznerRobotServerOrder) (*HetznerRobotServerTransaction, error) {
+	return c.postServerOrder(ctx, fmt.Sprintf("%s/order/server/transaction", c.url), order)
+}
+
+// orderServerMarketProduct buys a server from the server auction, see orderServer.
+func (c *HetznerRobotClient) orderServerMarketProduct(ctx context.Context, order HetznerRobotServerOrder) (*HetznerRobotServerTransaction, error) {
+	return c.postServerOrder(ctx, fmt.Sprintf("%s/order/server_market/transaction", c.url), order)
+}
+
+func (c *HetznerRobotClient) getServerMarketTransaction(ctx context.Context, id string) (*HetznerRobotServerTransaction, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/order/server_market/transaction/%s", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := HetznerRobotServerTransactionResponse{}
+	if err = json.Unmarshal(bytes, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction.Transaction, nil
+}
+
+func (c *HetznerRobotClient) getServerMarketProducts(ctx context.Context) ([]HetznerRobotServerMarketProduct, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/order/server_market/product", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if IsNotFound(err) {
+			return []HetznerRobotServerMarketProduct{}, nil
+		}
+		return nil, err
+	}
+
+	productResponses := []HetznerRobotServerMarketProductResponse{}
+	if err = json.Unmarshal(bytes, &productResponses); err != nil {
+		return nil, err
+	}
+
+	products := make([]HetznerRobotServerMarketProduct, len(productResponses))
+	for i, productResponse := range productResponses {
+		products[i] = productResponse.Product
+	}
+	return products, nil
+}
+
+func (c *HetznerRobotClient) postServerOrder(ctx context.Context, uri string, order HetznerRobotServerOrder) (*HetznerRobotServerTransaction, error) {
+	data := url.Values{}
+	data.Set("product_id", order.ProductID)
+	if order.Dist != "" {
+		data.Set("dist", order.Dist)
+	}
+	if order.Arch != 0 {
+		data.Set("arch", strconv.Itoa(order.Arch))
+	}
+	if order.Lang != "" {
+		data.Set("lang", order.Lang)
+	}
+	if order.Location != "" {
+		data.Set("location", order.Location)
+	}
+	for _, fingerprint := range order.AuthorizedKeys {
+		data.Add("authorized_key[]", fingerprint)
+	}
+	for _, addon := range order.Addons {
+		data.Add("addon[]", addon)
+	}
+	if order.Comment != "" {
+		data.Set("comment", order.Comment)
+	}
+	if order.Test {
+		data.Set("test", "true")
+	}
+
+	bytes, err := c.makeAPICall(ctx, "POST", uri, data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := HetznerRobotServerTransactionResponse{}
+	if err = json.Unmarshal(bytes, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction.Transaction, nil
+}
+
+type HetznerRobotServerAddonProductResponse struct {
+	Product HetznerRobotServerAddonProduct `json:"product"`
+}
+
+type HetznerRobotServerAddonProduct struct {
+	ID    string                   `json:"id"`
+	Name  string                   `json:"name"`
+	Type  string                   `json:"type"`
+	Price HetznerRobotProductPrice `json:"price"`
+}
+
+type HetznerRobotServerAddonTransactionResponse struct {
+	Transaction HetznerRobotServerAddonTransaction `json:"transaction"`
+}
+
+type HetznerRobotServerAddonTransaction struct {
+	ID        string                            `json:"id"`
+	Date      string                            `json:"date"`
+	Status    string                            `json:"status"`
+	Product   HetznerRobotServerAddonProduct    `json:"product"`
+	Resources []HetznerRobotServerAddonResource `json:"resources"`
+}
+
+// HetznerRobotServerAddonResource is an IP or subnet allocated by an addon order.
+type HetznerRobotServerAddonResource struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type HetznerRobotServerAddonOrder struct {
+	ServerNumber int
+	ProductID    string
+	Reason       string
+	Gateway      string
+	Test         bool
+}
+
+func (c *HetznerRobotClient) getServerAddonProducts(ctx context.Context, serverNumber int) ([]HetznerRobotServerAddonProduct, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/order/server_addon/%d/product", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	productResponses := []HetznerRobotServerAddonProductResponse{}
+	if err = json.Unmarshal(bytes, &productResponses); err != nil {
+		return nil, err
+	}
+
+	products := make([]HetznerRobotServerAddonProduct, len(productResponses))
+	for i, productResponse := range productResponses {
+		products[i] = productResponse.Product
+	}
+	return products, nil
+}
+
+func (c *HetznerRobotClient) getServerAddonTransaction(ctx context.Context, id string) (*HetznerRobotServerAddonTransaction, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/order/server_addon/transaction/%s", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := HetznerRobotServerAddonTransactionResponse{}
+	if err = json.Unmarshal(bytes, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction.Transaction, nil
+}
+
+// orderServerAddon orders an addon for a server, see orderServer.
+func (c *HetznerRobotClient) orderServerAddon(ctx context.Context, order HetznerRobotServerAddonOrder) (*HetznerRobotServerAddonTransaction, error) {
+	data := url.Values{}
+	data.Set("server_number", strconv.Itoa(order.ServerNumber))
+	data.Set("product_id", order.ProductID)
+	if order.Reason != "" {
+		data.Set("reason", order.Reason)
+	}
+	if order.Gateway != "" {
+		data.Set("gateway", order.Gateway)
+	}
+	if order.Test {
+		data.Set("test", "true")
+	}
+
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/order/server_addon/transaction", c.url), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := HetznerRobotServerAddonTransactionResponse{}
+	if err = json.Unmarshal(bytes, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction.Transaction, nil
+}
@@ -0,0 +1,126 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#reverse-dns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type HetznerRobotRdnsResponse struct {
+	Rdns HetznerRobotRdns `json:"rdns"`
+}
+
+type HetznerRobotRdns struct {
+	IP  string `json:"ip"`
+	PTR string `json:"ptr"`
+}
+
+func (c *HetznerRobotClient) getRdns(ctx context.Context, ip string) (*HetznerRobotRdns, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/rdns/%s", c.url, ip), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	rdns := HetznerRobotRdnsResponse{}
+	if err = json.Unmarshal(bytes, &rdns); err != nil {
+		return nil, err
+	}
+	return &rdns.Rdns, nil
+}
+
+// getRdnsEntries lists the reverse DNS entries of the account, or of one
+// server if serverIP is set.
+func (c *HetznerRobotClient) getRdnsEntries(ctx context.Context, serverIP string) ([]HetznerRobotRdns, error) {
+	uri := fmt.Sprintf("%s/rdns", c.url)
+	if serverIP != "" {
+		uri = fmt.Sprintf("%s?server_ip=%s", uri, url.QueryEscape(serverIP))
+	}
+
+	bytes, err := c.makeAPICall(ctx, "GET", uri, nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if IsNotFound(err) {
+			return []HetznerRobotRdns{}, nil
+		}
+		return nil, err
+	}
+
+	rdnsResponses := []HetznerRobotRdnsResponse{}
+	if err = json.Unmarshal(bytes, &rdnsResponses); err != nil {
+		return nil, err
+	}
+
+	entries := make([]HetznerRobotRdns, len(rdnsResponses))
+	for i, rdnsResponse := range rdnsResponses {
+		entries[i] = rdnsResponse.Rdns
+	}
+	return entries, nil
+}
+
+// setRdns creates the entry for ip or updates an existing one, also one set
+// outside Terraform. Robot's PUT only creates and fails with
+// RDNS_ALREADY_EXISTS, POST creates or updates and may be repeated.
+func (c *HetznerRobotClient) setRdns(ctx context.Context, ip string, ptr string) (*HetznerRobotRdns, error) {
+	data := url.Values{}
+	data.Set("ptr", ptr)
+
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/rdns/%s", c.url, ip), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	rdns := HetznerRobotRdnsResponse{}
+	if err = json.Unmarshal(bytes, &rdns); err != nil {
+		return nil, err
+	}
+	return &rdns.Rdns, nil
+}
+
+func (c *HetznerRobotClient) deleteRdns(ctx context.Context, ip string) error {
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/rdns/%s", c.url, ip), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// serversOwnIP reports whether ip is a main, additional or subnet address of one of servers.
+func serversOwnIP(servers []HetznerRobotServer, ip net.IP) bool {
+	for _, server := range servers {
+		if server.ServerIP != "" && ip.Equal(net.ParseIP(server.ServerIP)) {
+			return true
+		}
+		for _, serverIP := range server.IPs {
+			if ip.Equal(net.ParseIP(serverIP)) {
+				return true
+			}
+		}
+		// Robot reports the main IPv6 net without its mask, it is always a /64
+		if server.ServerIPv6 != "" && subnetContains(server.ServerIPv6, "64", ip) {
+			return true
+		}
+		for _, subnet := range server.Subnets {
+			if subnetContains(subnet.IP, subnet.Mask, ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func subnetContains(subnetIP string, mask string, ip net.IP) bool {
+	if dotted := net.ParseIP(mask).To4(); dotted != nil {
+		ones, _ := net.IPMask(dotted).Size()
+		mask = strconv.Itoa(ones)
+	}
+	_, network, err := net.ParseCIDR(fmt.Sprintf("%s/%s", subnetIP, mask))
+	if err != nil {
+		return false
+	}
+	return network.Contains(ip)
+}
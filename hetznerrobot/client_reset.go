@@ -0,0 +1,65 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#reset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type HetznerRobotResetOptionsResponse struct {
+	Reset HetznerRobotResetOptions `json:"reset"`
+}
+
+type HetznerRobotResetOptions struct {
+	ServerIP        string   `json:"server_ip"`
+	ServerIPv6      string   `json:"server_ipv6_net"`
+	ServerNumber    int      `json:"server_number"`
+	Types           []string `json:"type"`
+	OperatingStatus string   `json:"operating_status"`
+}
+
+type HetznerRobotResetResponse struct {
+	Reset HetznerRobotReset `json:"reset"`
+}
+
+type HetznerRobotReset struct {
+	ServerIP   string `json:"server_ip"`
+	ServerIPv6 string `json:"server_ipv6_net"`
+	Type       string `json:"type"`
+}
+
+func (c *HetznerRobotClient) getResetOptions(ctx context.Context, serverNumber int) (*HetznerRobotResetOptions, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/reset/%d", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	options := HetznerRobotResetOptionsResponse{}
+	if err = json.Unmarshal(bytes, &options); err != nil {
+		return nil, err
+	}
+	return &options.Reset, nil
+}
+
+// resetServer triggers a reset. Only rate limited attempts are retried, Robot
+// rejected those unprocessed; a retried reset that got through would reboot
+// the server twice.
+func (c *HetznerRobotClient) resetServer(ctx context.Context, serverNumber int, resetType string) (*HetznerRobotReset, error) {
+	data := url.Values{}
+	data.Set("type", resetType)
+
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/reset/%d", c.url, serverNumber), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	reset := HetznerRobotResetResponse{}
+	if err = json.Unmarshal(bytes, &reset); err != nil {
+		return nil, err
+	}
+	return &reset.Reset, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 type HetznerRobotServerResponse struct {
@@ -41,10 +42,6 @@ type HetznerRobotServer struct {
 	HotSwap bool `json:"hot_swap"`
 }
 
-type HetznerRobotServerRenameRequestBody struct {
-	Name string `json:"server_name"`
-}
-
 type HetznerRobotServersResponse struct {
 	Server []HetznerRobotServer `json:"server"`
 }
@@ -106,3 +103,19 @@ func (c *HetznerRobotClient) getServers(ctx context.Context) ([]HetznerRobotServ
 
 	return servers, nil
 }
+
+func (c *HetznerRobotClient) renameServer(ctx context.Context, serverNumber int, name string) (*HetznerRobotServer, error) {
+	data := url.Values{}
+	data.Set("server_name", name)
+
+	res, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/server/%d", c.url, serverNumber), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	serverResponse := HetznerRobotServerResponse{}
+	if err = json.Unmarshal(res, &serverResponse); err != nil {
+		return nil, err
+	}
+	return &serverResponse.Server, nil
+}
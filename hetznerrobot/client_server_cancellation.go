@@ -0,0 +1,87 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#server-cancellation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type HetznerRobotServerCancellationResponse struct {
+	Cancellation HetznerRobotServerCancellation `json:"cancellation"`
+}
+
+type HetznerRobotServerCancellation struct {
+	ServerIP                 string `json:"server_ip"`
+	ServerIPv6               string `json:"server_ipv6_net"`
+	ServerNumber             int    `json:"server_number"`
+	ServerName               string `json:"server_name"`
+	EarliestCancellationDate string `json:"earliest_cancellation_date"`
+	Cancelled                bool   `json:"cancelled"`
+	ReservationPossible      bool   `json:"reservation_possible"`
+	Reserved                 bool   `json:"reserved"`
+	CancellationDate         string `json:"cancellation_date"`
+	// Reasons lists the selectable cancellation reasons as long as the server
+	// is not cancelled, Reason the chosen one afterwards.
+	Reasons []string `json:"-"`
+	Reason  string   `json:"-"`
+
+	RawReason json.RawMessage `json:"cancellation_reason"`
+}
+
+func (c *HetznerRobotClient) getServerCancellation(ctx context.Context, serverNumber int) (*HetznerRobotServerCancellation, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/server/%d/cancellation", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseServerCancellation(bytes)
+}
+
+func (c *HetznerRobotClient) cancelServer(ctx context.Context, serverNumber int, cancellationDate string, reason string, reserveLocation bool) (*HetznerRobotServerCancellation, error) {
+	data := url.Values{}
+	data.Set("cancellation_date", cancellationDate)
+	if reason != "" {
+		data.Set("cancellation_reason", reason)
+	}
+	if reserveLocation {
+		data.Set("reserve_location", "true")
+	}
+
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/server/%d/cancellation", c.url, serverNumber), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseServerCancellation(bytes)
+}
+
+func (c *HetznerRobotClient) revokeServerCancellation(ctx context.Context, serverNumber int) error {
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/server/%d/cancellation", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseServerCancellation decodes a cancellation, whose cancellation_reason is
+// either the list of selectable reasons or the reason given on cancellation.
+func parseServerCancellation(bytes []byte) (*HetznerRobotServerCancellation, error) {
+	cancellationResponse := HetznerRobotServerCancellationResponse{}
+	if err := json.Unmarshal(bytes, &cancellationResponse); err != nil {
+		return nil, err
+	}
+
+	cancellation := cancellationResponse.Cancellation
+	if len(cancellation.RawReason) > 0 && string(cancellation.RawReason) != "null" {
+		if err := json.Unmarshal(cancellation.RawReason, &cancellation.Reasons); err != nil {
+			if err := json.Unmarshal(cancellation.RawReason, &cancellation.Reason); err != nil {
+				return nil, fmt.Errorf("unexpected cancellation_reason %s: %w", cancellation.RawReason, err)
+			}
+		}
+	}
+	return &cancellation, nil
+}
@@ -57,7 +57,7 @@ func (c *HetznerRobotClient) updateSshKey(ctx context.Context, keyFingerprint st
 	body := url.Values{}
 	body.Set("name", newName)
 
-	bytes, err := c.makeAPICall(ctx, "PUT", fmt.Sprintf("%s/key/%s", c.url, keyFingerprint), body, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	bytes, err := c.makeIdempotentAPICall(ctx, "PUT", fmt.Sprintf("%s/key/%s", c.url, keyFingerprint), body, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +71,7 @@ func (c *HetznerRobotClient) updateSshKey(ctx context.Context, keyFingerprint st
 }
 
 func (c *HetznerRobotClient) deleteSshKey(ctx context.Context, keyFingerprint string) error {
-	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/key/%s", c.url, keyFingerprint), nil, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	_, err := c.makeIdempotentAPICall(ctx, "DELETE", fmt.Sprintf("%s/key/%s", c.url, keyFingerprint), nil, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
 	if err != nil {
 		return err
 	}
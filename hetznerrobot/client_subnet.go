@@ -0,0 +1,72 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#subnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type HetznerRobotSubnetResponse struct {
+	Subnet HetznerRobotSubnet `json:"subnet"`
+}
+
+type HetznerRobotSubnet struct {
+	IP           string `json:"ip"`
+	Mask         int    `json:"mask"`
+	Gateway      string `json:"gateway"`
+	ServerIP     string `json:"server_ip"`
+	ServerNumber int    `json:"server_number"`
+	Failover     bool   `json:"failover"`
+	Locked       bool   `json:"locked"`
+	HetznerRobotTrafficWarnings
+}
+
+func (c *HetznerRobotClient) getSubnets(ctx context.Context) ([]HetznerRobotSubnet, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/subnet", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if IsNotFound(err) {
+			return []HetznerRobotSubnet{}, nil
+		}
+		return nil, err
+	}
+
+	subnetResponses := []HetznerRobotSubnetResponse{}
+	if err = json.Unmarshal(bytes, &subnetResponses); err != nil {
+		return nil, err
+	}
+
+	subnets := make([]HetznerRobotSubnet, len(subnetResponses))
+	for i, subnetResponse := range subnetResponses {
+		subnets[i] = subnetResponse.Subnet
+	}
+	return subnets, nil
+}
+
+func (c *HetznerRobotClient) getSubnet(ctx context.Context, netIP string) (*HetznerRobotSubnet, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/subnet/%s", c.url, netIP), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	subnet := HetznerRobotSubnetResponse{}
+	if err = json.Unmarshal(bytes, &subnet); err != nil {
+		return nil, err
+	}
+	return &subnet.Subnet, nil
+}
+
+func (c *HetznerRobotClient) setSubnetTrafficWarnings(ctx context.Context, netIP string, warnings HetznerRobotTrafficWarnings) (*HetznerRobotSubnet, error) {
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/subnet/%s", c.url, netIP), encodeTrafficWarnings(warnings), []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	subnet := HetznerRobotSubnetResponse{}
+	if err = json.Unmarshal(bytes, &subnet); err != nil {
+		return nil, err
+	}
+	return &subnet.Subnet, nil
+}
@@ -0,0 +1,99 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	rateLimited := newRobotAPIError(http.StatusForbidden, []byte(`{"error":{"status":403,"code":"RATE_LIMIT_EXCEEDED"}}`))
+	serverError := newRobotAPIError(http.StatusServiceUnavailable, []byte("unavailable"))
+	badGateway := newRobotAPIError(http.StatusBadGateway, []byte("bad gateway"))
+	invalidInput := newRobotAPIError(http.StatusBadRequest, []byte(`{"error":{"status":400,"code":"INVALID_INPUT"}}`))
+	notFound := newRobotAPIError(http.StatusNotFound, []byte(`{"error":{"status":404,"code":"SERVER_NOT_FOUND"}}`))
+	networkError := errors.New("error sending request: connection reset by peer")
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		idempotent bool
+		want       bool
+	}{
+		{"rate limited idempotent", context.Background(), rateLimited, true, true},
+		{"rate limited non-idempotent", context.Background(), rateLimited, false, true},
+		{"server error idempotent", context.Background(), serverError, true, true},
+		{"server error non-idempotent", context.Background(), serverError, false, false},
+		{"bad gateway idempotent", context.Background(), badGateway, true, true},
+		{"network error idempotent", context.Background(), networkError, true, true},
+		{"network error non-idempotent", context.Background(), networkError, false, false},
+		{"invalid input idempotent", context.Background(), invalidInput, true, false},
+		{"not found idempotent", context.Background(), notFound, true, false},
+		{"cancelled context", cancelled, rateLimited, true, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isRetryable(tc.ctx, tc.err, tc.idempotent); got != tc.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	c := NewHetznerRobotClient("", "", "", 3, 10*time.Minute)
+	serverError := newRobotAPIError(http.StatusServiceUnavailable, []byte("unavailable"))
+
+	t.Run("exponential backoff", func(t *testing.T) {
+		for attempt := 0; attempt < 4; attempt++ {
+			backoff := retryMinWait << uint(attempt)
+			wait := c.retryWait(attempt, "server", serverError)
+			if wait < backoff/2 || wait >= backoff {
+				t.Errorf("attempt %d: wait %s not in [%s, %s)", attempt, wait, backoff/2, backoff)
+			}
+		}
+	})
+
+	t.Run("documented limit floor", func(t *testing.T) {
+		rateLimited := newRobotAPIError(http.StatusForbidden, []byte(`{"error":{"status":403,"code":"RATE_LIMIT_EXCEEDED"}}`))
+		floor := robotEndpointLimits["reset"].interval()
+		wait := c.retryWait(0, "reset", rateLimited)
+		if wait < floor || wait >= floor+floor/10 {
+			t.Errorf("wait %s not in [%s, %s)", wait, floor, floor+floor/10)
+		}
+	})
+
+	t.Run("reported limit floor", func(t *testing.T) {
+		rateLimited := newRobotAPIError(http.StatusForbidden, []byte(`{"error":{"status":403,"code":"RATE_LIMIT_EXCEEDED","max_request":10,"interval":60}}`))
+		wait := c.retryWait(0, "reset", rateLimited)
+		if wait < 6*time.Second || wait >= 6*time.Second+600*time.Millisecond {
+			t.Errorf("wait %s not based on the reported limit of 10 per minute", wait)
+		}
+	})
+
+	t.Run("retry after floor", func(t *testing.T) {
+		rateLimited := newRobotAPIError(http.StatusForbidden, []byte(`{"error":{"status":403,"code":"RATE_LIMIT_EXCEEDED","max_request":10,"interval":60}}`))
+		rateLimited.RetryAfter = 20 * time.Second
+		wait := c.retryWait(0, "reset", rateLimited)
+		if wait < 20*time.Second || wait >= 22*time.Second {
+			t.Errorf("wait %s does not honour Retry-After", wait)
+		}
+	})
+
+	t.Run("cap", func(t *testing.T) {
+		capped := NewHetznerRobotClient("", "", "", 3, 5*time.Second)
+		if wait := capped.retryWait(10, "server", serverError); wait != 5*time.Second {
+			t.Errorf("backoff wait %s, want cap of 5s", wait)
+		}
+		rateLimited := newRobotAPIError(http.StatusForbidden, []byte(`{"error":{"status":403,"code":"RATE_LIMIT_EXCEEDED"}}`))
+		if wait := capped.retryWait(0, "reset", rateLimited); wait != 5*time.Second {
+			t.Errorf("rate limit wait %s, want cap of 5s", wait)
+		}
+	})
+}
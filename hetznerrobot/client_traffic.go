@@ -0,0 +1,133 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#traffic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type HetznerRobotTrafficResponse struct {
+	Traffic HetznerRobotTraffic `json:"traffic"`
+}
+
+// HetznerRobotTraffic holds the traffic of each queried IP or subnet. Data is
+// keyed by address and holds a total, SingleData additionally holds the values
+// per day, hour or month when single values were requested.
+type HetznerRobotTraffic struct {
+	Type       string                                          `json:"type"`
+	From       string                                          `json:"from"`
+	To         string                                          `json:"to"`
+	Data       map[string]HetznerRobotTrafficValues            `json:"-"`
+	SingleData map[string]map[string]HetznerRobotTrafficValues `json:"-"`
+
+	RawData json.RawMessage `json:"data"`
+}
+
+// HetznerRobotTrafficValues are traffic amounts in GB.
+type HetznerRobotTrafficValues struct {
+	In  trafficAmount `json:"in"`
+	Out trafficAmount `json:"out"`
+	Sum trafficAmount `json:"sum"`
+}
+
+// trafficAmount decodes an amount Robot sends either as a number or as a string.
+type trafficAmount float64
+
+func (a *trafficAmount) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		*a = 0
+		return nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid traffic amount %s: %w", data, err)
+	}
+	*a = trafficAmount(amount)
+	return nil
+}
+
+type HetznerRobotTrafficQuery struct {
+	IPs          []string
+	Subnets      []string
+	Type         string
+	From         string
+	To           string
+	SingleValues bool
+}
+
+func (c *HetznerRobotClient) getTraffic(ctx context.Context, query HetznerRobotTrafficQuery) (*HetznerRobotTraffic, error) {
+	data := url.Values{}
+	for _, ip := range query.IPs {
+		data.Add("ip[]", ip)
+	}
+	for _, subnet := range query.Subnets {
+		data.Add("subnet[]", subnet)
+	}
+	data.Set("type", query.Type)
+	data.Set("from", query.From)
+	data.Set("to", query.To)
+	data.Set("single_values", strconv.FormatBool(query.SingleValues))
+
+	// the query is sent as POST but does not change anything
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/traffic", c.url), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	trafficResponse := HetznerRobotTrafficResponse{}
+	if err = json.Unmarshal(bytes, &trafficResponse); err != nil {
+		return nil, err
+	}
+
+	traffic := trafficResponse.Traffic
+	traffic.Data = map[string]HetznerRobotTrafficValues{}
+	traffic.SingleData = map[string]map[string]HetznerRobotTrafficValues{}
+
+	rawData := map[string]json.RawMessage{}
+	if !isEmptyJSONArray(traffic.RawData) {
+		if err = json.Unmarshal(traffic.RawData, &rawData); err != nil {
+			return nil, err
+		}
+	}
+	for address, raw := range rawData {
+		if isEmptyJSONArray(raw) {
+			traffic.Data[address] = HetznerRobotTrafficValues{}
+			continue
+		}
+		if !query.SingleValues {
+			values := HetznerRobotTrafficValues{}
+			if err = json.Unmarshal(raw, &values); err != nil {
+				return nil, err
+			}
+			traffic.Data[address] = values
+			continue
+		}
+
+		singleValues := map[string]HetznerRobotTrafficValues{}
+		if err = json.Unmarshal(raw, &singleValues); err != nil {
+			return nil, err
+		}
+		total := HetznerRobotTrafficValues{}
+		for _, values := range singleValues {
+			total.In += values.In
+			total.Out += values.Out
+			total.Sum += values.Sum
+		}
+		traffic.Data[address] = total
+		traffic.SingleData[address] = singleValues
+	}
+	return &traffic, nil
+}
+
+// isEmptyJSONArray reports whether raw is an empty array, which Robot sends
+// instead of an empty object when there is no traffic data.
+func isEmptyJSONArray(raw json.RawMessage) bool {
+	return strings.Join(strings.Fields(string(raw)), "") == "[]" || len(raw) == 0
+}
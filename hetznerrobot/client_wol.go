@@ -0,0 +1,33 @@
+package hetznerrobot
+
+// https://robot.your-server.de/doc/webservice/en.html#wake-on-lan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type HetznerRobotWolResponse struct {
+	Wol HetznerRobotWol `json:"wol"`
+}
+
+type HetznerRobotWol struct {
+	ServerIP     string `json:"server_ip"`
+	ServerIPv6   string `json:"server_ipv6_net"`
+	ServerNumber int    `json:"server_number"`
+}
+
+func (c *HetznerRobotClient) sendWol(ctx context.Context, serverNumber int) (*HetznerRobotWol, error) {
+	bytes, err := c.makeIdempotentAPICall(ctx, "POST", fmt.Sprintf("%s/wol/%d", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	wol := HetznerRobotWolResponse{}
+	if err = json.Unmarshal(bytes, &wol); err != nil {
+		return nil, err
+	}
+	return &wol.Wol, nil
+}
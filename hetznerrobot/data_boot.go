@@ -11,17 +11,27 @@ func dataBoot() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceBootRead,
 		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Server ID",
+			},
 			// read-only / computed
 			"active_profile": {
-				Type:        schema.TypeString, // Enum should be better (linux/rescue/...)
+				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "Active boot profile",
+				Description: "Active boot profile (linux, rescue, vnc, windows, plesk or cpanel)",
 			},
 			"architecture": {
 				Type:        schema.TypeString, // Enum should be better (amd64/...)
 				Computed:    true,
 				Description: "Active Architecture",
 			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hostname of the Plesk or cPanel installation",
+			},
 			"ipv4_address": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -48,15 +58,13 @@ func dataBoot() *schema.Resource {
 				Description: "Current Rescue System root password / Linux installation password or null",
 				Sensitive:   true,
 			},
+			"authorized_keys": bootKeysSchema("Authorized SSH keys of the linux installation or rescue system"),
+			"host_keys":       bootKeysSchema("Host keys of the rescue system or installation"),
 		},
-		/*
-			AuthorizedKeys []string		    authorized_key (Array)	Authorized public SSH keys
-			HostKeys []string				host_key (Array)	Host keys
-		*/
 	}
 }
 func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	serverID := d.Get("server_id").(int)
 	boot, err := c.getBoot(ctx, serverID)
@@ -68,9 +76,12 @@ func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interf
 	d.Set("architecture", boot.Architecture)
 	d.Set("ipv4_address", boot.ServerIPv4)
 	d.Set("ipv6_network", boot.ServerIPv6)
+	d.Set("hostname", boot.Hostname)
 	d.Set("language", boot.Language)
 	d.Set("operating_system", boot.OperatingSystem)
 	d.Set("password", boot.Password)
+	d.Set("authorized_keys", flattenBootKeys(boot.AuthorizedKeys))
+	d.Set("host_keys", flattenBootKeys(boot.HostKeys))
 	d.SetId(strconv.Itoa(serverID))
 
 	// Warning or errors can be collected in a slice type
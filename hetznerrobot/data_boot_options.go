@@ -0,0 +1,94 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataBootOptions() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"server_id": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Server ID",
+		},
+	}
+	// read-only / computed
+	for _, profile := range bootProfiles {
+		s[profile] = &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Options of the " + profile + " boot profile, empty if the server does not offer it",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"active": {
+						Type:        schema.TypeBool,
+						Computed:    true,
+						Description: "Whether the profile is active, the options then only hold the values in use",
+					},
+					"operating_systems": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "Distributions, or rescue operating systems",
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"architectures": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"languages": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceBootOptionsRead,
+		Schema:      s,
+	}
+}
+
+func dataSourceBootOptionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverID := d.Get("server_id").(int)
+	for _, profile := range bootProfiles {
+		options, err := c.getBootOptions(ctx, serverID, profile)
+		if err != nil {
+			if IsNotFound(err) {
+				d.Set(profile, []map[string]interface{}{})
+				continue
+			}
+			return diag.Errorf("Unable to find %s boot options for server ID %d:\n\t %q", profile, serverID, err)
+		}
+
+		if err := d.Set(profile, []map[string]interface{}{{
+			"active":            options.Active,
+			"operating_systems": bootOperatingSystems(profile, options),
+			"architectures":     options.Architecture,
+			"languages":         options.Language,
+		}}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(strconv.Itoa(serverID))
+
+	return nil
+}
+
+// bootOperatingSystems returns the values operating_system accepts for profile.
+func bootOperatingSystems(profile string, options *BootOptions) []string {
+	if profile == bootProfileRescue {
+		return options.OS
+	}
+	return options.Distribution
+}
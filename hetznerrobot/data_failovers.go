@@ -0,0 +1,78 @@
+package hetznerrobot
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataFailovers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFailoversRead,
+		Schema: map[string]*schema.Schema{
+			"failovers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Failover IPs of the account",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"netmask": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_ipv6": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"active_server_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFailoversRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	failovers, err := c.getFailovers(ctx)
+	if err != nil {
+		return diag.Errorf("Unable to list failover IPs:\n\t %q", err)
+	}
+
+	failoverList := make([]map[string]interface{}, len(failovers))
+	for i, failover := range failovers {
+		failoverList[i] = map[string]interface{}{
+			"ip":               failover.IP,
+			"netmask":          failover.Netmask,
+			"server_ip":        failover.ServerIP,
+			"server_ipv6":      failover.ServerIPv6,
+			"server_number":    failover.ServerNumber,
+			"active_server_ip": failover.ActiveServerIP,
+		}
+	}
+
+	if err := d.Set("failovers", failoverList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("failovers")
+
+	return nil
+}
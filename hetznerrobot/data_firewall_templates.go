@@ -0,0 +1,73 @@
+package hetznerrobot
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataFirewallTemplates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFirewallTemplatesRead,
+		Schema: map[string]*schema.Schema{
+			"templates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Firewall templates of the account",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_default": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"whitelist_hos": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"filter_ipv6": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceFirewallTemplatesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	templates, err := c.getFirewallTemplates(ctx)
+	if err != nil {
+		return diag.Errorf("Unable to list firewall templates:\n\t %q", err)
+	}
+
+	templateList := make([]map[string]interface{}, len(templates))
+	for i, template := range templates {
+		templateList[i] = map[string]interface{}{
+			"id":            template.ID,
+			"name":          template.Name,
+			"is_default":    template.IsDefault,
+			"whitelist_hos": template.WhitelistHetznerServices,
+			"filter_ipv6":   template.FilterIPv6,
+		}
+	}
+
+	if err := d.Set("templates", templateList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("firewall_templates")
+
+	return nil
+}
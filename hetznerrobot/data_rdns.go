@@ -0,0 +1,103 @@
+package hetznerrobot
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataRdns() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRdnsRead,
+		Schema: map[string]*schema.Schema{
+			"ip": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "IPv4 or IPv6 address",
+			},
+			// read-only / computed
+			"ptr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PTR record",
+			},
+		},
+	}
+}
+
+func dataRdnsEntries() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRdnsEntriesRead,
+		Schema: map[string]*schema.Schema{
+			"server_ip": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the entries of the server with this main IP",
+			},
+			// read-only / computed
+			"entries": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Reverse DNS entries",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ptr": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRdnsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Get("ip").(string)
+	rdns, err := c.getRdns(ctx, ip)
+	if err != nil {
+		return diag.Errorf("Unable to find reverse DNS entry for %s:\n\t %q", ip, err)
+	}
+
+	d.Set("ptr", rdns.PTR)
+	d.SetId(rdns.IP)
+
+	return nil
+}
+
+func dataSourceRdnsEntriesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverIP := d.Get("server_ip").(string)
+	entries, err := c.getRdnsEntries(ctx, serverIP)
+	if err != nil {
+		return diag.Errorf("Unable to list reverse DNS entries:\n\t %q", err)
+	}
+
+	entryList := make([]map[string]interface{}, len(entries))
+	for i, entry := range entries {
+		entryList[i] = map[string]interface{}{
+			"ip":  entry.IP,
+			"ptr": entry.PTR,
+		}
+	}
+
+	if err := d.Set("entries", entryList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if serverIP != "" {
+		d.SetId("rdns_" + serverIP)
+	} else {
+		d.SetId("rdns")
+	}
+
+	return nil
+}
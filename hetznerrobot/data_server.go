@@ -251,25 +251,17 @@ func dataServer() *schema.Resource {
 }
 
 func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	serverNumber := d.Get("server_number").(int)
 
 	server, err := c.getServer(ctx, serverNumber)
 	if err != nil {
-		return diag.Errorf("Unable to find Server with IP %s:\n\t %q", serverNumber, err)
+		return diag.Errorf("Unable to find Server with number %d:\n\t %q", serverNumber, err)
+	}
+	for key, value := range flattenServer(server) {
+		d.Set(key, value)
 	}
-	d.Set("datacenter", server.DataCenter)
-	d.Set("is_cancelled", server.Cancelled)
-	d.Set("paid_until", server.PaidUntil)
-	d.Set("product", server.Product)
-	d.Set("server_ip_addresses", server.IPs)
-	d.Set("server_ip", server.ServerIP)
-	d.Set("server_ip_v6_net", server.ServerIPv6)
-	d.Set("server_name", server.ServerName)
-	d.Set("server_subnets", server.Subnets)
-	d.Set("status", server.Status)
-	d.Set("traffic", server.Traffic)
 	d.SetId(strconv.Itoa(server.ServerNumber))
 
 	// Warning or errors can be collected in a slice type
@@ -279,7 +271,7 @@ func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta inte
 }
 
 func dataSourceServersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(HetznerRobotClient)
+	client := meta.(*HetznerRobotClient)
 
 	servers, err := client.getServers(ctx)
 	if err != nil {
@@ -294,39 +286,7 @@ func dataSourceServersRead(ctx context.Context, d *schema.ResourceData, meta int
 		// Debug: Print details of each server being processed
 		fmt.Printf("Processing server %d: %+v\n", i, server)
 
-		// Convert subnets to the correct format
-		subnets := make([]map[string]interface{}, len(server.Subnets))
-		for j, subnet := range server.Subnets {
-			subnets[j] = map[string]interface{}{
-				"ip":   subnet.IP,
-				"mask": subnet.Mask,
-			}
-		}
-
-		serverMap := map[string]interface{}{
-			"server_number":     server.ServerNumber,
-			"server_name":       server.ServerName,
-			"server_ip":         server.ServerIP,
-			"server_ipv6":       server.ServerIPv6,
-			"datacenter":        server.DataCenter,
-			"is_cancelled":      server.Cancelled,
-			"paid_until":        server.PaidUntil,
-			"product":           server.Product,
-			"ip_addresses":      server.IPs,
-			"server_subnets":    subnets,
-			"status":            server.Status,
-			"traffic":           server.Traffic,
-			"linked_storagebox": server.LinkedStoragebox,
-			"reset":             server.Reset,
-			"rescue":            server.Rescue,
-			"vnc":               server.VNC,
-			"windows":           server.Windows,
-			"plesk":             server.Plesk,
-			"cpanel":            server.CPanel,
-			"wol":               server.Wol,
-			"hot_swap":          server.HotSwap,
-		}
-		serverList[i] = serverMap
+		serverList[i] = flattenServer(&server)
 	}
 
 	// Debug: Print number of servers being set in state
@@ -340,3 +300,37 @@ func dataSourceServersRead(ctx context.Context, d *schema.ResourceData, meta int
 
 	return nil
 }
+
+func flattenServer(server *HetznerRobotServer) map[string]interface{} {
+	subnets := make([]map[string]interface{}, len(server.Subnets))
+	for j, subnet := range server.Subnets {
+		subnets[j] = map[string]interface{}{
+			"ip":   subnet.IP,
+			"mask": subnet.Mask,
+		}
+	}
+
+	return map[string]interface{}{
+		"server_number":     server.ServerNumber,
+		"server_name":       server.ServerName,
+		"server_ip":         server.ServerIP,
+		"server_ipv6":       server.ServerIPv6,
+		"datacenter":        server.DataCenter,
+		"is_cancelled":      server.Cancelled,
+		"paid_until":        server.PaidUntil,
+		"product":           server.Product,
+		"ip_addresses":      server.IPs,
+		"server_subnets":    subnets,
+		"status":            server.Status,
+		"traffic":           server.Traffic,
+		"linked_storagebox": server.LinkedStoragebox,
+		"reset":             server.Reset,
+		"rescue":            server.Rescue,
+		"vnc":               server.VNC,
+		"windows":           server.Windows,
+		"plesk":             server.Plesk,
+		"cpanel":            server.CPanel,
+		"wol":               server.Wol,
+		"hot_swap":          server.HotSwap,
+	}
+}
@@ -0,0 +1,73 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataServerAddons() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerAddonsRead,
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Server number",
+			},
+			// read-only / computed
+			"addons": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Addons orderable for the server",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"prices": productPricesSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerAddonsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	addons, err := c.getServerAddonProducts(ctx, serverNumber)
+	if err != nil {
+		return diag.Errorf("Unable to list addons of server %d:\n\t %q", serverNumber, err)
+	}
+
+	addonList := make([]map[string]interface{}, len(addons))
+	for i, addon := range addons {
+		addonList[i] = map[string]interface{}{
+			"id":     addon.ID,
+			"name":   addon.Name,
+			"type":   addon.Type,
+			"prices": flattenProductPrices([]HetznerRobotProductPrice{addon.Price}),
+		}
+	}
+
+	if err := d.Set("addons", addonList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.Itoa(serverNumber))
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataServerCancellation() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerCancellationRead,
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Server number",
+			},
+			// read-only / computed
+			"earliest_cancellation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Earliest possible cancellation date",
+			},
+			"cancelled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the server is cancelled",
+			},
+			"cancellation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Date the server is cancelled on, empty if it is not cancelled",
+			},
+			"cancellation_reason": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Reason given for the cancellation, empty if it is not cancelled",
+			},
+			"reasons": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Selectable cancellation reasons, empty if the server is cancelled",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"reservation_possible": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the server location can be reserved",
+			},
+			"reserved": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the server location is reserved",
+			},
+		},
+	}
+}
+
+func dataSourceServerCancellationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	cancellation, err := c.getServerCancellation(ctx, serverNumber)
+	if err != nil {
+		return diag.Errorf("Unable to find cancellation options of server %d:\n\t %q", serverNumber, err)
+	}
+
+	d.Set("earliest_cancellation_date", cancellation.EarliestCancellationDate)
+	d.Set("cancelled", cancellation.Cancelled)
+	d.Set("cancellation_date", cancellation.CancellationDate)
+	d.Set("cancellation_reason", cancellation.Reason)
+	d.Set("reasons", cancellation.Reasons)
+	d.Set("reservation_possible", cancellation.ReservationPossible)
+	d.Set("reserved", cancellation.Reserved)
+	d.SetId(strconv.Itoa(serverNumber))
+
+	return nil
+}
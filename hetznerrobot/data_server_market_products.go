@@ -0,0 +1,268 @@
+package hetznerrobot
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// serverMarketProductSortKeys maps the sort_by values to the compared product value.
+var serverMarketProductSortKeys = map[string]func(HetznerRobotServerMarketProduct) float64{
+	"price": func(p HetznerRobotServerMarketProduct) float64 {
+		price, _ := strconv.ParseFloat(p.Price, 64)
+		return price
+	},
+	"memory_size":   func(p HetznerRobotServerMarketProduct) float64 { return p.MemorySize },
+	"disk_size":     func(p HetznerRobotServerMarketProduct) float64 { return serverMarketDiskSize(p) },
+	"cpu_benchmark": func(p HetznerRobotServerMarketProduct) float64 { return float64(p.CPUBenchmark) },
+	"next_reduce":   func(p HetznerRobotServerMarketProduct) float64 { return float64(p.NextReduce) },
+}
+
+func dataServerMarketProducts() *schema.Resource {
+	sortKeys := make([]string, 0, len(serverMarketProductSortKeys))
+	for key := range serverMarketProductSortKeys {
+		sortKeys = append(sortKeys, key)
+	}
+	sort.Strings(sortKeys)
+
+	return &schema.Resource{
+		ReadContext: dataSourceServerMarketProductsRead,
+		Schema: map[string]*schema.Schema{
+			"min_ram": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "Only list servers with at least this much memory in GB",
+			},
+			"min_disk": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "Only list servers with at least this much total disk space in GB",
+			},
+			"cpu_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsValidRegExp),
+				Description:      "Only list servers whose CPU matches this regular expression",
+			},
+			"datacenter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list servers in this datacenter or location, e.g. FSN1-DC8 or FSN1",
+			},
+			"max_price": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "Only list servers with a monthly net price in EUR of at most this amount",
+			},
+			"fixed_price": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only list servers with (true) or without (false) a fixed price",
+			},
+			"max_next_reduce": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Only list servers whose price is reduced next within this many seconds",
+			},
+			"sort_by": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "price",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(sortKeys, false)),
+				Description:      "Sort the servers by one of " + strings.Join(sortKeys, ", "),
+			},
+			"sort_order": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "asc",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"asc", "desc"}, false)),
+				Description:      "Sort order, asc or desc",
+			},
+			// read-only / computed
+			"products": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Servers offered in the server auction",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"traffic": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dist": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"lang": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"cpu": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cpu_benchmark": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory_size": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"hdd_size": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"hdd_text": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hdd_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"datacenter": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"network_speed": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"price": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"price_hourly": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"price_setup": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"fixed_price": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"next_reduce": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"next_reduce_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerMarketProductsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	products, err := c.getServerMarketProducts(ctx)
+	if err != nil {
+		return diag.Errorf("Unable to list server market products:\n\t %q", err)
+	}
+
+	var cpuPattern *regexp.Regexp
+	if v, ok := d.GetOk("cpu_regex"); ok {
+		cpuPattern = regexp.MustCompile(v.(string))
+	}
+	datacenter := d.Get("datacenter").(string)
+	minRAM, hasMinRAM := d.GetOk("min_ram")
+	minDisk, hasMinDisk := d.GetOk("min_disk")
+	maxPrice, hasMaxPrice := d.GetOk("max_price")
+	fixedPrice, hasFixedPrice := d.GetOkExists("fixed_price")
+	maxNextReduce, hasMaxNextReduce := d.GetOk("max_next_reduce")
+
+	matching := make([]HetznerRobotServerMarketProduct, 0, len(products))
+	for _, product := range products {
+		switch {
+		case cpuPattern != nil && !cpuPattern.MatchString(product.CPU),
+			datacenter != "" && product.Datacenter != datacenter && !strings.HasPrefix(product.Datacenter, datacenter+"-"),
+			hasMinRAM && product.MemorySize < minRAM.(float64),
+			hasMinDisk && serverMarketDiskSize(product) < minDisk.(float64),
+			hasMaxPrice && serverMarketProductSortKeys["price"](product) > maxPrice.(float64),
+			hasFixedPrice && product.FixedPrice != fixedPrice.(bool),
+			hasMaxNextReduce && (product.FixedPrice || product.NextReduce > maxNextReduce.(int)):
+			continue
+		}
+		matching = append(matching, product)
+	}
+
+	sortKey := serverMarketProductSortKeys[d.Get("sort_by").(string)]
+	descending := d.Get("sort_order").(string) == "desc"
+	sort.SliceStable(matching, func(i, j int) bool {
+		if descending {
+			return sortKey(matching[i]) > sortKey(matching[j])
+		}
+		return sortKey(matching[i]) < sortKey(matching[j])
+	})
+
+	productList := make([]map[string]interface{}, len(matching))
+	for i, product := range matching {
+		productList[i] = map[string]interface{}{
+			"id":               product.ID,
+			"name":             product.Name,
+			"description":      product.Description,
+			"traffic":          product.Traffic,
+			"dist":             product.Dist,
+			"lang":             product.Lang,
+			"cpu":              product.CPU,
+			"cpu_benchmark":    product.CPUBenchmark,
+			"memory_size":      product.MemorySize,
+			"hdd_size":         product.HddSize,
+			"hdd_text":         product.HddText,
+			"hdd_count":        product.HddCount,
+			"datacenter":       product.Datacenter,
+			"network_speed":    product.NetworkSpeed,
+			"price":            product.Price,
+			"price_hourly":     product.PriceHourly,
+			"price_setup":      product.PriceSetup,
+			"fixed_price":      product.FixedPrice,
+			"next_reduce":      product.NextReduce,
+			"next_reduce_date": product.NextReduceDate,
+		}
+	}
+
+	if err := d.Set("products", productList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("server_market_products")
+
+	return nil
+}
+
+// serverMarketDiskSize returns the total disk space of product in GB.
+func serverMarketDiskSize(product HetznerRobotServerMarketProduct) float64 {
+	if product.HddCount == 0 {
+		return product.HddSize
+	}
+	return product.HddSize * float64(product.HddCount)
+}
@@ -0,0 +1,55 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testServerMarketProducts = `[
+{"product":{"id":2001,"name":"SB45","description":["Intel Core i7-6700"],"traffic":"unlimited","dist":["Rescue system"],"lang":["en"],"cpu":"Intel Core i7-6700","cpu_benchmark":8130,"memory_size":64,"hdd_size":512,"hdd_text":"2x SSD M.2 NVMe 512 GB","hdd_count":2,"datacenter":"FSN1-DC14","network_speed":"1 Gbit/s","price":"39.0000","price_hourly":"0.0625","price_setup":"0.0000","fixed_price":false,"next_reduce":3600,"next_reduce_date":"2026-10-17 13:00:00","orderable_addons":[]}},
+{"product":{"id":2002,"name":"SB58","description":["AMD Ryzen 7 3700X"],"traffic":"unlimited","dist":["Rescue system"],"lang":["en"],"cpu":"AMD Ryzen 7 3700X","cpu_benchmark":22700,"memory_size":64,"hdd_size":4096,"hdd_text":"2x HDD 4,0 TB","hdd_count":2,"datacenter":"HEL1-DC2","network_speed":"1 Gbit/s","price":"45.0000","price_hourly":"0.0721","price_setup":"0.0000","fixed_price":true,"next_reduce":0,"next_reduce_date":"","orderable_addons":[]}},
+{"product":{"id":2003,"name":"SB32","description":["Intel Xeon E3-1275V6"],"traffic":"unlimited","dist":["Rescue system"],"lang":["en"],"cpu":"Intel Xeon E3-1275V6","cpu_benchmark":8900,"memory_size":32,"hdd_size":480,"hdd_text":"SSD 480 GB","hdd_count":0,"datacenter":"FSN1-DC1","network_speed":"1 Gbit/s","price":"33.5000","price_hourly":"0.0537","price_setup":"0.0000","fixed_price":false,"next_reduce":7200,"next_reduce_date":"2026-10-17 14:00:00","orderable_addons":[]}}
+]`
+
+func TestDataSourceServerMarketProductsRead(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   []int
+	}{
+		{"cheapest first", map[string]interface{}{}, []int{2003, 2001, 2002}},
+		{"by cpu benchmark descending", map[string]interface{}{"sort_by": "cpu_benchmark", "sort_order": "desc"}, []int{2002, 2003, 2001}},
+		{"by total disk size", map[string]interface{}{"sort_by": "disk_size"}, []int{2003, 2001, 2002}},
+		{"cpu", map[string]interface{}{"cpu_regex": "^Intel"}, []int{2003, 2001}},
+		{"location", map[string]interface{}{"datacenter": "FSN1"}, []int{2003, 2001}},
+		{"datacenter", map[string]interface{}{"datacenter": "FSN1-DC1"}, []int{2003}},
+		{"memory and disk", map[string]interface{}{"min_ram": 64.0, "min_disk": 2000.0}, []int{2002}},
+		{"max price", map[string]interface{}{"max_price": 39.0}, []int{2003, 2001}},
+		{"without fixed price", map[string]interface{}{"fixed_price": false}, []int{2003, 2001}},
+		{"reduced soon", map[string]interface{}{"max_next_reduce": 3600}, []int{2001}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{"GET /order/server_market/product": testServerMarketProducts})
+			d := schema.TestResourceDataRaw(t, dataServerMarketProducts().Schema, tc.config)
+			if diags := dataSourceServerMarketProductsRead(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("read failed: %v", diags)
+			}
+			products := d.Get("products").([]interface{})
+			got := make([]int, len(products))
+			for i, product := range products {
+				got[i] = product.(map[string]interface{})["id"].(int)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("products = %v, want %v", got, tc.want)
+			}
+			for i := range tc.want {
+				if got[i] != tc.want[i] {
+					t.Fatalf("products = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func productPricesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Monthly and setup prices in EUR per location",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"location": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"price_net": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"price_gross": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"price_setup_net": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"price_setup_gross": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+func dataServerProducts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerProductsRead,
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list products orderable in this location, e.g. FSN1",
+			},
+			"max_price": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "Only list products with a monthly net price in EUR of at most this amount",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list products whose ID or name contains this string, ignoring case",
+			},
+			// read-only / computed
+			"products": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Orderable server products",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"traffic": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dist": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"lang": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"locations": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"prices": productPricesSchema(),
+						"orderable_addons": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"min": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"max": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"prices": productPricesSchema(),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerProductsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	products, err := c.getServerProducts(ctx)
+	if err != nil {
+		return diag.Errorf("Unable to list server products:\n\t %q", err)
+	}
+
+	location := d.Get("location").(string)
+	maxPrice, hasMaxPrice := d.GetOk("max_price")
+	name := strings.ToLower(d.Get("name").(string))
+
+	productList := make([]map[string]interface{}, 0, len(products))
+	for _, product := range products {
+		if location != "" && !stringInSlice(location, product.Location) {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(product.ID), name) && !strings.Contains(strings.ToLower(product.Name), name) {
+			continue
+		}
+		if hasMaxPrice && !productPricedAtMost(product.Prices, location, maxPrice.(float64)) {
+			continue
+		}
+
+		addons := make([]map[string]interface{}, len(product.OrderableAddons))
+		for i, addon := range product.OrderableAddons {
+			addons[i] = map[string]interface{}{
+				"id":     addon.ID,
+				"name":   addon.Name,
+				"min":    addon.Min,
+				"max":    addon.Max,
+				"prices": flattenProductPrices(addon.Prices),
+			}
+		}
+
+		productList = append(productList, map[string]interface{}{
+			"id":               product.ID,
+			"name":             product.Name,
+			"description":      product.Description,
+			"traffic":          product.Traffic,
+			"dist":             product.Dist,
+			"lang":             product.Lang,
+			"locations":        product.Location,
+			"prices":           flattenProductPrices(product.Prices),
+			"orderable_addons": addons,
+		})
+	}
+
+	if err := d.Set("products", productList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("server_products")
+
+	return nil
+}
+
+// productPricedAtMost reports whether the monthly net price in location, or in
+// any location if it is empty, is at most maxPrice.
+func productPricedAtMost(prices []HetznerRobotProductPrice, location string, maxPrice float64) bool {
+	for _, price := range prices {
+		if location != "" && price.Location != location {
+			continue
+		}
+		if price.Price.netAmount() <= maxPrice {
+			return true
+		}
+	}
+	return false
+}
+
+func flattenProductPrices(prices []HetznerRobotProductPrice) []map[string]interface{} {
+	priceList := make([]map[string]interface{}, len(prices))
+	for i, price := range prices {
+		priceList[i] = map[string]interface{}{
+			"location":          price.Location,
+			"price_net":         price.Price.Net,
+			"price_gross":       price.Price.Gross,
+			"price_setup_net":   price.PriceSetup.Net,
+			"price_setup_gross": price.PriceSetup.Gross,
+		}
+	}
+	return priceList
+}
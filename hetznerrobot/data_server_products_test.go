@@ -0,0 +1,63 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testServerProducts = `[
+{"product":{"id":"EX44","name":"Dedicated Server EX44","description":["Intel Core i5-13500"],"traffic":"unlimited","dist":["Rescue system"],"lang":["en"],"location":["FSN1","HEL1"],
+ "prices":[{"location":"FSN1","price":{"net":"44.0000","gross":"52.3600"},"price_setup":{"net":"0.0000","gross":"0.0000"}},{"location":"HEL1","price":{"net":"39.0000","gross":"46.4100"},"price_setup":{"net":"0.0000","gross":"0.0000"}}],
+ "orderable_addons":[{"id":"primary_ipv4","name":"Primary IPv4","min":0,"max":1,"prices":[{"location":"FSN1","price":{"net":"1.7000","gross":"2.0230"},"price_setup":{"net":"0.0000","gross":"0.0000"}}]}]}},
+{"product":{"id":"AX102","name":"Dedicated Server AX102","description":["AMD Ryzen 9 7950X3D"],"traffic":"unlimited","dist":["Rescue system"],"lang":["en"],"location":["FSN1"],
+ "prices":[{"location":"FSN1","price":{"net":"104.0000","gross":"123.7600"},"price_setup":{"net":"0.0000","gross":"0.0000"}}],
+ "orderable_addons":[]}}
+]`
+
+func TestDataSourceServerProductsRead(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   []string
+	}{
+		{"all", map[string]interface{}{}, []string{"EX44", "AX102"}},
+		{"location", map[string]interface{}{"location": "HEL1"}, []string{"EX44"}},
+		{"name ignoring case", map[string]interface{}{"name": "ax"}, []string{"AX102"}},
+		{"max price in any location", map[string]interface{}{"max_price": 40.0}, []string{"EX44"}},
+		{"max price in location", map[string]interface{}{"location": "FSN1", "max_price": 40.0}, []string{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{"GET /order/server/product": testServerProducts})
+			d := schema.TestResourceDataRaw(t, dataServerProducts().Schema, tc.config)
+			if diags := dataSourceServerProductsRead(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("read failed: %v", diags)
+			}
+			products := d.Get("products").([]interface{})
+			if len(products) != len(tc.want) {
+				t.Fatalf("got %d products, want %v", len(products), tc.want)
+			}
+			for i, id := range tc.want {
+				if got := products[i].(map[string]interface{})["id"]; got != id {
+					t.Errorf("product %d = %v, want %s", i, got, id)
+				}
+			}
+		})
+	}
+}
+
+func TestDataSourceServerProductsFlattensPrices(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{"GET /order/server/product": testServerProducts})
+	d := schema.TestResourceDataRaw(t, dataServerProducts().Schema, map[string]interface{}{"name": "EX44"})
+	if diags := dataSourceServerProductsRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if got := d.Get("products.0.prices.1.price_gross").(string); got != "46.4100" {
+		t.Errorf("HEL1 gross price = %q, want 46.4100", got)
+	}
+	if got := d.Get("products.0.orderable_addons.0.prices.0.price_net").(string); got != "1.7000" {
+		t.Errorf("addon net price = %q, want 1.7000", got)
+	}
+}
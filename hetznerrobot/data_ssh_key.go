@@ -46,7 +46,7 @@ func dataSshKey() *schema.Resource {
 }
 
 func dataSourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	keyFingerprint := d.Id()
 
@@ -0,0 +1,122 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSubnets() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceSubnetsRead,
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Only list the subnets routed to the server with this number",
+			},
+			// read-only / computed
+			"subnets": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Subnets of the account",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mask": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"gateway": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"failover": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"locked": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"traffic_warnings": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"traffic_hourly": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"traffic_daily": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"traffic_monthly": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSubnetsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	subnets, err := c.getSubnets(ctx)
+	if err != nil {
+		return diag.Errorf("Unable to list subnets:\n\t %q", err)
+	}
+
+	serverNumber := d.Get("server_number").(int)
+	subnetList := make([]map[string]interface{}, 0, len(subnets))
+	for i := range subnets {
+		if serverNumber != 0 && subnets[i].ServerNumber != serverNumber {
+			continue
+		}
+		subnetList = append(subnetList, flattenSubnet(&subnets[i]))
+	}
+
+	if err := d.Set("subnets", subnetList); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if serverNumber != 0 {
+		d.SetId(fmt.Sprintf("subnets_%d", serverNumber))
+	} else {
+		d.SetId("subnets")
+	}
+
+	return nil
+}
+
+func flattenSubnet(subnet *HetznerRobotSubnet) map[string]interface{} {
+	return map[string]interface{}{
+		"ip":               subnet.IP,
+		"mask":             subnet.Mask,
+		"gateway":          subnet.Gateway,
+		"server_ip":        subnet.ServerIP,
+		"server_number":    subnet.ServerNumber,
+		"failover":         subnet.Failover,
+		"locked":           subnet.Locked,
+		"traffic_warnings": subnet.TrafficWarnings,
+		"traffic_hourly":   subnet.TrafficHourly,
+		"traffic_daily":    subnet.TrafficDaily,
+		"traffic_monthly":  subnet.TrafficMonthly,
+	}
+}
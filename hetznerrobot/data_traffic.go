@@ -0,0 +1,195 @@
+package hetznerrobot
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// trafficRangePatterns holds the from/to format Robot expects per traffic type.
+var trafficRangePatterns = map[string]struct {
+	pattern *regexp.Regexp
+	format  string
+}{
+	"day":   {regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}$`), "YYYY-MM-DDTHH"},
+	"month": {regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`), "YYYY-MM-DD"},
+	"year":  {regexp.MustCompile(`^\d{4}-\d{2}$`), "YYYY-MM"},
+}
+
+func trafficValuesSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"in": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Incoming traffic in GB",
+		},
+		"out": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Outgoing traffic in GB",
+		},
+		"sum": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Total traffic in GB",
+		},
+	}
+}
+
+func dataTraffic() *schema.Resource {
+	valuesSchema := trafficValuesSchema()
+	valuesSchema["key"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Hour, day or month of the value, depending on type",
+	}
+
+	seriesSchema := trafficValuesSchema()
+	seriesSchema["address"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "IP address or subnet",
+	}
+	seriesSchema["values"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Single values, only set if single_values is true",
+		Elem:        &schema.Resource{Schema: valuesSchema},
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceTrafficRead,
+		Schema: map[string]*schema.Schema{
+			"ips": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				Description:  "IP addresses to query",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				AtLeastOneOf: []string{"ips", "subnets"},
+			},
+			"subnets": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				Description:  "Subnets to query",
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				AtLeastOneOf: []string{"ips", "subnets"},
+			},
+			"type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"day", "month", "year"}, false)),
+				Description:      "Range type: day (from/to as YYYY-MM-DDTHH), month (YYYY-MM-DD) or year (YYYY-MM)",
+			},
+			"from": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Start of the range",
+			},
+			"to": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "End of the range",
+			},
+			"single_values": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Return the values per hour, day or month instead of only totals",
+			},
+			// read-only / computed
+			"series": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Traffic per IP address or subnet, sorted by address",
+				Elem:        &schema.Resource{Schema: seriesSchema},
+			},
+		},
+	}
+}
+
+func dataSourceTrafficRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	query := HetznerRobotTrafficQuery{
+		IPs:          expandStringList(d.Get("ips").([]interface{})),
+		Subnets:      expandStringList(d.Get("subnets").([]interface{})),
+		Type:         d.Get("type").(string),
+		From:         d.Get("from").(string),
+		To:           d.Get("to").(string),
+		SingleValues: d.Get("single_values").(bool),
+	}
+
+	rangePattern := trafficRangePatterns[query.Type]
+	for _, key := range []string{"from", "to"} {
+		if value := d.Get(key).(string); !rangePattern.pattern.MatchString(value) {
+			return diag.Errorf("%s: %q must be formatted as %s for type %q", key, value, rangePattern.format, query.Type)
+		}
+	}
+
+	traffic, err := c.getTraffic(ctx, query)
+	if err != nil {
+		return diag.Errorf("Unable to get traffic:\n\t %q", err)
+	}
+
+	addresses := make([]string, 0, len(traffic.Data))
+	for address := range traffic.Data {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	series := make([]map[string]interface{}, len(addresses))
+	for i, address := range addresses {
+		entry := flattenTrafficValues(traffic.Data[address])
+		entry["address"] = address
+
+		singleValues := traffic.SingleData[address]
+		keys := make([]string, 0, len(singleValues))
+		for key := range singleValues {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		values := make([]map[string]interface{}, len(keys))
+		for j, key := range keys {
+			values[j] = flattenTrafficValues(singleValues[key])
+			values[j]["key"] = key
+		}
+		entry["values"] = values
+
+		series[i] = entry
+	}
+
+	if err := d.Set("series", series); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join([]string{
+		strings.Join(query.IPs, ","), strings.Join(query.Subnets, ","),
+		query.Type, query.From, query.To, fmt.Sprint(query.SingleValues),
+	}, "|")))))
+
+	return nil
+}
+
+func flattenTrafficValues(values HetznerRobotTrafficValues) map[string]interface{} {
+	return map[string]interface{}{
+		"in":  float64(values.In),
+		"out": float64(values.Out),
+		"sum": float64(values.Sum),
+	}
+}
+
+func expandStringList(list []interface{}) []string {
+	result := make([]string, len(list))
+	for i, item := range list {
+		result[i] = item.(string)
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package hetznerrobot
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestTrafficAmountUnmarshal(t *testing.T) {
+	cases := []struct {
+		json    string
+		want    trafficAmount
+		wantErr bool
+	}{
+		{`12.5`, 12.5, false},
+		{`"12.5"`, 12.5, false},
+		{`"0"`, 0, false},
+		{`""`, 0, false},
+		{`null`, 0, false},
+		{`"lots"`, 0, true},
+	}
+	for _, tc := range cases {
+		var got trafficAmount
+		err := json.Unmarshal([]byte(tc.json), &got)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("unmarshal %s = %v, %v, want %v (error %v)", tc.json, got, err, tc.want, tc.wantErr)
+		}
+	}
+}
+
+func TestGetTraffic(t *testing.T) {
+	cases := []struct {
+		name         string
+		singleValues bool
+		response     string
+		want         map[string]HetznerRobotTrafficValues
+		wantSingle   int
+	}{
+		{"totals", false,
+			`{"traffic":{"type":"month","from":"2026-09-01","to":"2026-09-30","data":{"192.0.2.1":{"in":"1.5","out":2.25,"sum":3.75},"192.0.2.10":[]}}}`,
+			map[string]HetznerRobotTrafficValues{"192.0.2.1": {In: 1.5, Out: 2.25, Sum: 3.75}, "192.0.2.10": {}}, 0},
+		{"single values", true,
+			`{"traffic":{"type":"month","from":"2026-09-01","to":"2026-09-02","data":{"192.0.2.1":{"01":{"in":1,"out":2,"sum":3},"02":{"in":"0.5","out":"0.5","sum":"1"}}}}}`,
+			map[string]HetznerRobotTrafficValues{"192.0.2.1": {In: 1.5, Out: 2.5, Sum: 4}}, 2},
+		{"no data", false,
+			`{"traffic":{"type":"month","from":"2026-09-01","to":"2026-09-30","data":[]}}`,
+			map[string]HetznerRobotTrafficValues{}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			robot, c := newRobotAPIStandIn(t, map[string]string{"POST /traffic": tc.response})
+			traffic, err := c.getTraffic(context.Background(), HetznerRobotTrafficQuery{
+				IPs:          []string{"192.0.2.1", "192.0.2.10"},
+				Type:         "month",
+				From:         "2026-09-01",
+				To:           "2026-09-30",
+				SingleValues: tc.singleValues,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(traffic.Data) != len(tc.want) {
+				t.Errorf("data = %+v, want %+v", traffic.Data, tc.want)
+			}
+			for address, want := range tc.want {
+				if traffic.Data[address] != want {
+					t.Errorf("data[%s] = %+v, want %+v", address, traffic.Data[address], want)
+				}
+			}
+			if got := len(traffic.SingleData["192.0.2.1"]); got != tc.wantSingle {
+				t.Errorf("got %d single values, want %d", got, tc.wantSingle)
+			}
+			if writes := robot.writes(); len(writes) != 1 || !strings.Contains(writes[0], "ip%5B%5D=192.0.2.1&ip%5B%5D=192.0.2.10") {
+				t.Errorf("requests = %v, want the IPs as ip[]", writes)
+			}
+		})
+	}
+}
+
+func TestDataSourceTrafficRead(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /traffic": `{"traffic":{"type":"day","from":"2026-09-01T00","to":"2026-09-01T23","data":{"198.51.100.0":{"in":1,"out":1,"sum":2},"192.0.2.1":{"in":3,"out":4,"sum":7}}}}`,
+	})
+
+	d := schema.TestResourceDataRaw(t, dataTraffic().Schema, map[string]interface{}{
+		"ips": []interface{}{"192.0.2.1"}, "subnets": []interface{}{"198.51.100.0"},
+		"type": "day", "from": "2026-09-01T00", "to": "2026-09-01T23",
+	})
+	if diags := dataSourceTrafficRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("series.#").(int) != 2 || d.Get("series.0.address").(string) != "192.0.2.1" || d.Get("series.0.sum").(float64) != 7 {
+		t.Errorf("series = %v, want two entries sorted by address", d.Get("series"))
+	}
+
+	d = schema.TestResourceDataRaw(t, dataTraffic().Schema, map[string]interface{}{
+		"ips": []interface{}{"192.0.2.1"}, "type": "day", "from": "2026-09-01", "to": "2026-09-01T23",
+	})
+	diags := dataSourceTrafficRead(context.Background(), d, c)
+	if !diags.HasError() || !strings.HasPrefix(diags[0].Summary, `from: "2026-09-01" must be formatted as YYYY-MM-DDTHH`) {
+		t.Errorf("diagnostics = %v, want the from format rejected", diags)
+	}
+}
@@ -101,12 +101,12 @@ func dataVSwitch() *schema.Resource {
 }
 
 func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	vSwitchID := d.Id()
 	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
 	if err != nil {
-		return diag.Errorf("Unable to find VSwitch with ID %d:\n\t %q", vSwitchID, err)
+		return diag.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err)
 	}
 
 	d.Set("name", vSwitch.Name)
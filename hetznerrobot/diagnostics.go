@@ -0,0 +1,53 @@
+package hetznerrobot
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// attributePaths resolves Robot request parameter names to top-level resource attributes.
+func attributePaths(fields map[string]string) func(string) cty.Path {
+	return func(field string) cty.Path {
+		if attribute, ok := fields[field]; ok {
+			return cty.GetAttrPath(attribute)
+		}
+		return nil
+	}
+}
+
+// apiErrorDiagnostics converts err into diagnostics. When Robot rejected the
+// request because of missing or invalid parameters, one diagnostic per field is
+// returned, pointing at the attribute attributePath maps the parameter to.
+func apiErrorDiagnostics(err error, summary string, attributePath func(string) cty.Path) diag.Diagnostics {
+	apiErr, ok := asRobotAPIError(err)
+	if !ok || len(apiErr.Fields()) == 0 {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   err.Error(),
+		}}
+	}
+
+	var diags diag.Diagnostics
+	for _, field := range apiErr.Missing {
+		diags = append(diags, fieldDiagnostic(summary, field, "is required by Hetzner Robot", apiErr, attributePath))
+	}
+	for _, field := range apiErr.Invalid {
+		diags = append(diags, fieldDiagnostic(summary, field, "was rejected as invalid by Hetzner Robot", apiErr, attributePath))
+	}
+	return diags
+}
+
+func fieldDiagnostic(summary string, field string, reason string, apiErr *RobotAPIError, attributePath func(string) cty.Path) diag.Diagnostic {
+	d := diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("Parameter %q %s: %s", field, reason, apiErr.Message),
+	}
+	if attributePath != nil {
+		d.AttributePath = attributePath(field)
+	}
+	return d
+}
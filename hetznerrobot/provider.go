@@ -2,9 +2,11 @@ package hetznerrobot
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider -
@@ -26,19 +28,57 @@ func Provider() *schema.Provider {
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_URL", "https://robot-ws.your-server.de"),
 			},
+			"max_retries": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HETZNERROBOT_MAX_RETRIES", 5),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "Maximum number of retries for rate limited or transiently failing requests",
+			},
+			"retry_max_wait": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HETZNERROBOT_RETRY_MAX_WAIT", 120),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "Maximum time in seconds to wait between two retries",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"hetzner-robot_boot":     resourceBoot(),
-			"hetzner-robot_firewall": resourceFirewall(),
-			"hetzner-robot_vswitch":  resourceVSwitch(),
-			"hetzner-robot_ssh_key":  resourceSshKey(),
+			"hetzner-robot_boot":                resourceBoot(),
+			"hetzner-robot_failover":            resourceFailover(),
+			"hetzner-robot_firewall":            resourceFirewall(),
+			"hetzner-robot_firewall_template":   resourceFirewallTemplate(),
+			"hetzner-robot_installation":        resourceInstallation(),
+			"hetzner-robot_ip":                  resourceIP(),
+			"hetzner-robot_rdns":                resourceRdns(),
+			"hetzner-robot_server":              resourceServer(),
+			"hetzner-robot_server_addon":        resourceServerAddon(),
+			"hetzner-robot_server_cancellation": resourceServerCancellation(),
+			"hetzner-robot_server_market_order": resourceServerMarketOrder(),
+			"hetzner-robot_server_order":        resourceServerOrder(),
+			"hetzner-robot_server_reset":        resourceServerReset(),
+			"hetzner-robot_server_wol":          resourceServerWol(),
+			"hetzner-robot_vswitch":             resourceVSwitch(),
+			"hetzner-robot_ssh_key":             resourceSshKey(),
+			"hetzner-robot_subnet":              resourceSubnet(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"hetzner-robot_boot":    dataBoot(),
-			"hetzner-robot_server":  dataServer(),
-			"hetzner-robot_servers": dataServers(),
-			"hetzner-robot_vswitch": dataVSwitch(),
-			"hetzner-robot_ssh_key": dataSshKey(),
+			"hetzner-robot_boot":                   dataBoot(),
+			"hetzner-robot_boot_options":           dataBootOptions(),
+			"hetzner-robot_failovers":              dataFailovers(),
+			"hetzner-robot_firewall_templates":     dataFirewallTemplates(),
+			"hetzner-robot_rdns":                   dataRdns(),
+			"hetzner-robot_rdns_entries":           dataRdnsEntries(),
+			"hetzner-robot_server":                 dataServer(),
+			"hetzner-robot_server_addons":          dataServerAddons(),
+			"hetzner-robot_server_cancellation":    dataServerCancellation(),
+			"hetzner-robot_server_market_products": dataServerMarketProducts(),
+			"hetzner-robot_server_products":        dataServerProducts(),
+			"hetzner-robot_servers":                dataServers(),
+			"hetzner-robot_subnets":                dataSubnets(),
+			"hetzner-robot_traffic":                dataTraffic(),
+			"hetzner-robot_vswitch":                dataVSwitch(),
+			"hetzner-robot_ssh_key":                dataSshKey(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -48,8 +88,10 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	username := d.Get("username").(string)
 	password := d.Get("password").(string)
 	url := d.Get("url").(string)
+	maxRetries := d.Get("max_retries").(int)
+	retryMaxWait := time.Duration(d.Get("retry_max_wait").(int)) * time.Second
 
 	var diags diag.Diagnostics
 
-	return NewHetznerRobotClient(username, password, url), diags
+	return NewHetznerRobotClient(username, password, url, maxRetries, retryMaxWait), diags
 }
@@ -2,17 +2,36 @@ package hetznerrobot
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strconv"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// bootAttributePaths maps boot configuration request parameters to resource attributes.
+var bootAttributePaths = attributePaths(map[string]string{
+	"arch":           "architecture",
+	"authorized_key": "authorized_keys",
+	"dist":           "operating_system",
+	"hostname":       "hostname",
+	"lang":           "language",
+	"os":             "operating_system",
+})
+
+// bootSettingKeys are the attributes a boot profile is activated with.
+var bootSettingKeys = []string{"server_id", "active_profile", "architecture", "operating_system", "language", "hostname", "authorized_keys"}
+
 func resourceBoot() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceBootCreate,
 		ReadContext:   resourceBootRead,
 		UpdateContext: resourceBootUpdate,
 		DeleteContext: resourceBootDelete,
+		CustomizeDiff: resourceBootCustomizeDiff,
 
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceBootImportState,
@@ -26,9 +45,10 @@ func resourceBoot() *schema.Resource {
 			},
 			// optional
 			"active_profile": {
-				Type:        schema.TypeString, // Enum should be better (linux/rescue/...)
-				Optional:    true,
-				Description: "Active boot profile",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(bootProfiles, false)),
+				Description:      "Active boot profile, one of " + strings.Join(bootProfiles, ", "),
 			},
 			"architecture": {
 				Type:        schema.TypeString, // Enum should be better (amd64/...)
@@ -45,10 +65,21 @@ func resourceBoot() *schema.Resource {
 				Optional:    true,
 				Description: "Active Operating System / Distribution",
 			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Hostname of the Plesk or cPanel installation",
+			},
+			"keep_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave the active profile armed on destroy instead of deactivating it",
+			},
 			"authorized_keys": {
 				Type:        schema.TypeList,
 				Optional:    true,
-				Description: "One or more SSH key fingerprints",
+				Description: "One or more SSH key fingerprints, used by the linux and rescue profiles",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -70,12 +101,78 @@ func resourceBoot() *schema.Resource {
 				Description: "Current Rescue System root password / Linux installation password or null",
 				Sensitive:   true,
 			},
+			"host_keys": bootKeysSchema("Host keys of the rescue system or installation"),
+		},
+	}
+}
+
+func bootKeysSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"fingerprint": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"size": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+			},
 		},
 	}
 }
 
+func flattenBootKeys(keys []BootKey) []map[string]interface{} {
+	keyList := make([]map[string]interface{}, len(keys))
+	for i, key := range keys {
+		keyList[i] = map[string]interface{}{
+			"name":        key.Name,
+			"fingerprint": key.Fingerprint,
+			"type":        key.Type,
+			"size":        key.Size,
+		}
+	}
+	return keyList
+}
+
+// bootKeyFingerprints returns the fingerprints of keys, in the order of the
+// configured fingerprints first so that Robot's ordering does not show as drift.
+func bootKeyFingerprints(configured []interface{}, keys []BootKey) []string {
+	remaining := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		remaining[key.Fingerprint] = true
+	}
+
+	fingerprints := make([]string, 0, len(keys))
+	for _, fingerprint := range configured {
+		if remaining[fingerprint.(string)] {
+			fingerprints = append(fingerprints, fingerprint.(string))
+			delete(remaining, fingerprint.(string))
+		}
+	}
+	for _, key := range keys {
+		if remaining[key.Fingerprint] {
+			fingerprints = append(fingerprints, key.Fingerprint)
+			delete(remaining, key.Fingerprint)
+		}
+	}
+	return fingerprints
+}
+
 func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	serverID, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -91,10 +188,16 @@ func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("architecture", boot.Architecture)
 	d.Set("ipv4_address", boot.ServerIPv4)
 	d.Set("ipv6_network", boot.ServerIPv6)
+	d.Set("hostname", boot.Hostname)
 	d.Set("language", boot.Language)
 	d.Set("operating_system", boot.OperatingSystem)
 	d.Set("password", boot.Password)
+	d.Set("host_keys", flattenBootKeys(boot.HostKeys))
+	if boot.ActiveProfile == bootProfileLinux || boot.ActiveProfile == bootProfileRescue {
+		d.Set("authorized_keys", bootKeyFingerprints(nil, boot.AuthorizedKeys))
+	}
 	d.Set("server_id", serverID)
+	d.Set("keep_on_destroy", false)
 
 	results := make([]*schema.ResourceData, 1)
 	results[0] = d
@@ -102,13 +205,14 @@ func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta i
 }
 
 func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	serverID := d.Get("server_id").(int)
 	activeBootProfile := d.Get("active_profile").(string)
 	arch := d.Get("architecture").(string)
 	os := d.Get("operating_system").(string)
 	lang := d.Get("language").(string)
+	hostname := d.Get("hostname").(string)
 	authorizedKeys := make([]string, 0)
 	if input := d.Get("authorized_keys"); input != nil {
 		for _, key := range input.([]interface{}) {
@@ -116,14 +220,15 @@ func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta interf
 		}
 	}
 
-	bootProfile, err := c.setBootProfile(ctx, serverID, activeBootProfile, arch, os, lang, authorizedKeys)
+	bootProfile, err := c.setBootProfile(ctx, serverID, activeBootProfile, arch, os, lang, hostname, authorizedKeys)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to set boot profile %q for server ID %d", activeBootProfile, serverID), bootAttributePaths)
 	}
 
 	d.Set("ipv4_address", bootProfile.ServerIPv4)
 	d.Set("ipv6_network", bootProfile.ServerIPv6)
 	d.Set("password", bootProfile.Password)
+	d.Set("host_keys", flattenBootKeys(bootProfile.HostKeys))
 	d.SetId(strconv.Itoa(serverID))
 
 	// Warning or errors can be collected in a slice type
@@ -133,11 +238,18 @@ func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	serverID := d.Get("server_id").(int)
 	boot, err := c.getBoot(ctx, serverID)
 	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "boot configuration not found, removing from state", map[string]interface{}{
+				"server_id": serverID,
+			})
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(err)
 	}
 
@@ -145,9 +257,15 @@ func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	d.Set("architecture", boot.Architecture)
 	d.Set("ipv4_address", boot.ServerIPv4)
 	d.Set("ipv6_network", boot.ServerIPv6)
+	d.Set("hostname", boot.Hostname)
 	d.Set("language", boot.Language)
 	d.Set("operating_system", boot.OperatingSystem)
 	d.Set("password", boot.Password)
+	d.Set("host_keys", flattenBootKeys(boot.HostKeys))
+	// only the linux installation and the rescue system report authorized keys
+	if boot.ActiveProfile == bootProfileLinux || boot.ActiveProfile == bootProfileRescue {
+		d.Set("authorized_keys", bootKeyFingerprints(d.Get("authorized_keys").([]interface{}), boot.AuthorizedKeys))
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -156,13 +274,14 @@ func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta interfac
 }
 
 func resourceBootUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	serverID := d.Get("server_id").(int)
 	activeBootProfile := d.Get("active_profile").(string)
 	arch := d.Get("architecture").(string)
 	os := d.Get("operating_system").(string)
 	lang := d.Get("language").(string)
+	hostname := d.Get("hostname").(string)
 	authorizedKeys := make([]string, 0)
 	if input := d.Get("authorized_keys"); input != nil {
 		for _, key := range input.([]interface{}) {
@@ -170,14 +289,35 @@ func resourceBootUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 		}
 	}
 
-	bootProfile, err := c.setBootProfile(ctx, serverID, activeBootProfile, arch, os, lang, authorizedKeys)
+	// only keep_on_destroy changed
+	if !d.HasChanges(bootSettingKeys...) {
+		return nil
+	}
+
+	// Robot keeps every subsystem armed on its own and with the settings it
+	// was activated with, so deactivate the old one to switch or re-arm it
+	oldServerID, _ := d.GetChange("server_id")
+	oldProfile, _ := d.GetChange("active_profile")
+	if oldProfile.(string) != "" {
+		if err := c.deleteBootProfile(ctx, oldServerID.(int), oldProfile.(string)); err != nil && !IsNotFound(err) {
+			return diag.Errorf("Unable to deactivate boot profile %q for server ID %d:\n\t %q", oldProfile, oldServerID, err)
+		}
+	}
+	if activeBootProfile == "" {
+		d.Set("password", "")
+		d.Set("host_keys", flattenBootKeys(nil))
+		return nil
+	}
+
+	bootProfile, err := c.setBootProfile(ctx, serverID, activeBootProfile, arch, os, lang, hostname, authorizedKeys)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to set boot profile %q for server ID %d", activeBootProfile, serverID), bootAttributePaths)
 	}
 
 	d.Set("ipv4_address", bootProfile.ServerIPv4)
 	d.Set("ipv6_network", bootProfile.ServerIPv6)
 	d.Set("password", bootProfile.Password)
+	d.Set("host_keys", flattenBootKeys(bootProfile.HostKeys))
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -186,8 +326,21 @@ func resourceBootUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func resourceBootDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	c := meta.(*HetznerRobotClient)
 
-	return diags
+	serverID := d.Get("server_id").(int)
+	activeBootProfile := d.Get("active_profile").(string)
+	if d.Get("keep_on_destroy").(bool) || activeBootProfile == "" {
+		tflog.Info(ctx, "removing boot configuration from state, the active profile is kept", map[string]interface{}{
+			"server_id":      serverID,
+			"active_profile": activeBootProfile,
+		})
+		return nil
+	}
+
+	if err := c.deleteBootProfile(ctx, serverID, activeBootProfile); err != nil && !IsNotFound(err) {
+		return diag.Errorf("Unable to deactivate boot profile %q for server ID %d:\n\t %q", activeBootProfile, serverID, err)
+	}
+
+	return nil
 }
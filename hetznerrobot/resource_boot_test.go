@@ -0,0 +1,83 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceBootDelete(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile string
+		keep    bool
+		route   string
+		want    []string
+	}{
+		{"deactivated", bootProfileRescue, false, `{"rescue":{"server_ip":"192.0.2.1","server_number":1,"active":false}}`, []string{"DELETE /boot/1/rescue"}},
+		{"already inactive", bootProfileRescue, false, `{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`, []string{"DELETE /boot/1/rescue"}},
+		{"kept", bootProfileRescue, true, "", nil},
+		{"nothing active", "", false, "", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			robot, c := newRobotAPIStandIn(t, map[string]string{"DELETE /boot/1/rescue": tc.route})
+			d := resourceBoot().TestResourceData()
+			d.Set("server_id", 1)
+			d.Set("active_profile", tc.profile)
+			d.Set("keep_on_destroy", tc.keep)
+			if diags := resourceBootDelete(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("delete failed: %v", diags)
+			}
+			robot.checkWrites(t, tc.want...)
+		})
+	}
+}
+
+func TestResourceBootUpdate(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile string
+		os      string
+		want    []string
+	}{
+		{"to linux", bootProfileLinux, "Debian 12 base", []string{"DELETE /boot/1/rescue", "POST /boot/1/linux dist=Debian+12+base&lang=en"}},
+		{"to none", "", "", []string{"DELETE /boot/1/rescue"}},
+		// Robot keeps an armed subsystem's settings until it is deactivated
+		{"re-armed", bootProfileRescue, "vkvm", []string{"DELETE /boot/1/rescue", "POST /boot/1/rescue os=vkvm"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			robot, c := newRobotAPIStandIn(t, map[string]string{
+				"DELETE /boot/1/rescue": `{"rescue":{"server_ip":"192.0.2.1","server_number":1,"active":false}}`,
+				"POST /boot/1/rescue":   `{"rescue":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":1,"os":"vkvm","arch":64,"active":true,"password":"Resc4ePassw0rd2","authorized_key":[],"host_key":[],"boot_time":null}}`,
+				"POST /boot/1/linux":    `{"linux":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:111:4221::","server_number":1,"dist":"Debian 12 base","arch":64,"lang":"en","active":true,"password":"L1nuxPassw0rd","authorized_key":[],"host_key":[]}}`,
+			})
+			state := &terraform.InstanceState{ID: "1", Attributes: map[string]string{
+				"id":               "1",
+				"server_id":        "1",
+				"active_profile":   bootProfileRescue,
+				"operating_system": "linux",
+				"password":         "Resc4ePassw0rd",
+			}}
+			diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+				"active_profile":   {Old: bootProfileRescue, New: tc.profile},
+				"operating_system": {Old: "linux", New: tc.os},
+				"language":         {Old: "", New: "en"},
+			}}
+			d, err := schema.InternalMap(resourceBoot().Schema).Data(state, diff)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diags := resourceBootUpdate(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("update failed: %v", diags)
+			}
+			robot.checkWrites(t, tc.want...)
+			if got := d.Get("password").(string); tc.profile == "" && got != "" {
+				t.Errorf("password = %q, want it cleared", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceBootCustomizeDiff rejects boot profiles and profile options the
+// server does not offer, so they show up in terraform plan instead of failing
+// the apply.
+func resourceBootCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("active_profile") || !d.NewValueKnown("server_id") {
+		return nil
+	}
+	profile := d.Get("active_profile").(string)
+	if profile == "" {
+		return nil
+	}
+
+	var errs []error
+	if (profile == bootProfilePlesk || profile == bootProfileCPanel) && d.NewValueKnown("hostname") && d.Get("hostname").(string) == "" {
+		errs = append(errs, fmt.Errorf("hostname: required by the %s boot profile", profile))
+	}
+
+	if d.Id() == "" || d.HasChanges("active_profile", "server_id") {
+		c := meta.(*HetznerRobotClient)
+
+		serverID := d.Get("server_id").(int)
+		server, err := c.getServer(ctx, serverID)
+		if err != nil {
+			return fmt.Errorf("unable to find server %d: %w", serverID, err)
+		}
+		if !bootProfileAvailable(server, profile) {
+			return errors.Join(append(errs, fmt.Errorf("active_profile: server %d does not support the %s boot profile", serverID, profile))...)
+		}
+	}
+
+	if d.Id() == "" || d.HasChanges("active_profile", "server_id", "operating_system", "language", "architecture") {
+		errs = append(errs, validateBootOptions(ctx, d, meta.(*HetznerRobotClient), profile)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateBootOptions checks operating_system, language and architecture
+// against the values Robot offers for profile.
+func validateBootOptions(ctx context.Context, d *schema.ResourceDiff, c *HetznerRobotClient, profile string) []error {
+	serverID := d.Get("server_id").(int)
+	options, err := c.getBootOptions(ctx, serverID, profile)
+	if err != nil {
+		return []error{fmt.Errorf("unable to find %s boot options for server %d: %w", profile, serverID, err)}
+	}
+	// an active profile only reports the values in use, nothing to check against
+	if options.Active {
+		return nil
+	}
+
+	var errs []error
+	for _, field := range []struct {
+		key     string
+		offered []string
+	}{
+		{"operating_system", bootOperatingSystems(profile, options)},
+		{"language", options.Language},
+		{"architecture", options.Architecture},
+	} {
+		if !d.NewValueKnown(field.key) {
+			continue
+		}
+		value := d.Get(field.key).(string)
+		if value != "" && len(field.offered) > 0 && !stringInSlice(value, field.offered) {
+			errs = append(errs, fmt.Errorf("%s: %q is not offered by the %s boot profile of server %d, use one of %s",
+				field.key, value, profile, serverID, strings.Join(field.offered, ", ")))
+		}
+	}
+	return errs
+}
+
+// bootProfileAvailable checks profile against the capability flags of server.
+// Robot has no flag for the linux installation, it is always offered.
+func bootProfileAvailable(server *HetznerRobotServer, profile string) bool {
+	switch profile {
+	case bootProfileRescue:
+		return server.Rescue
+	case bootProfileVNC:
+		return server.VNC
+	case bootProfileWindows:
+		return server.Windows
+	case bootProfilePlesk:
+		return server.Plesk
+	case bootProfileCPanel:
+		return server.CPanel
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/tidwall/gjson"
+)
+
+// newBootStandIn serves server 1 with the given capability flags and the
+// boot options of each profile, a profile without options offers nothing to
+// check against.
+func newBootStandIn(t *testing.T, flags string, options map[string]string) *HetznerRobotClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile := strings.TrimPrefix(r.URL.Path, "/boot/1/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/server/1":
+			fmt.Fprintf(w, `{"server":{"server_ip":"192.0.2.1","server_number":1,"status":"ready",%s}}`, flags)
+		case r.Method == http.MethodGet && stringInSlice(profile, bootProfiles):
+			body, ok := options[profile]
+			if !ok {
+				body = `{"server_number":1,"active":false}`
+			}
+			fmt.Fprintf(w, `{%q:%s}`, profile, body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewHetznerRobotClient("user", "password", server.URL, 0, time.Second)
+}
+
+// planBoot runs the plan of a new boot resource with config.
+func planBoot(c *HetznerRobotClient, config map[string]interface{}) error {
+	_, err := resourceBoot().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), c)
+	return err
+}
+
+func TestBootProfileAvailable(t *testing.T) {
+	none := &HetznerRobotServer{}
+	all := &HetznerRobotServer{Rescue: true, VNC: true, Windows: true, Plesk: true, CPanel: true}
+
+	cases := []struct {
+		name    string
+		server  *HetznerRobotServer
+		profile string
+		want    bool
+	}{
+		{"linux is always offered", none, bootProfileLinux, true},
+		{"rescue", &HetznerRobotServer{Rescue: true}, bootProfileRescue, true},
+		{"no rescue", none, bootProfileRescue, false},
+		{"vnc", &HetznerRobotServer{VNC: true}, bootProfileVNC, true},
+		{"no vnc", none, bootProfileVNC, false},
+		{"windows", &HetznerRobotServer{Windows: true}, bootProfileWindows, true},
+		{"no windows", none, bootProfileWindows, false},
+		{"plesk", &HetznerRobotServer{Plesk: true}, bootProfilePlesk, true},
+		{"no plesk", none, bootProfilePlesk, false},
+		{"cpanel", &HetznerRobotServer{CPanel: true}, bootProfileCPanel, true},
+		{"no cpanel", none, bootProfileCPanel, false},
+		{"flag of another profile", &HetznerRobotServer{Plesk: true}, bootProfileCPanel, false},
+		{"everything offered", all, bootProfileWindows, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bootProfileAvailable(tc.server, tc.profile); got != tc.want {
+				t.Errorf("bootProfileAvailable() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceBootCustomizeDiffProfile(t *testing.T) {
+	cases := []struct {
+		name    string
+		flags   string
+		config  map[string]interface{}
+		wantErr []string
+	}{
+		{"linux without flags", `"rescue":false,"vnc":false`, map[string]interface{}{"server_id": 1, "active_profile": "linux"}, nil},
+		{"no profile", `"rescue":false`, map[string]interface{}{"server_id": 1}, nil},
+		{"offered", `"vnc":true`, map[string]interface{}{"server_id": 1, "active_profile": "vnc"}, nil},
+		{"not offered", `"vnc":false`, map[string]interface{}{"server_id": 1, "active_profile": "vnc"},
+			[]string{"active_profile: server 1 does not support the vnc boot profile"}},
+		{"plesk with hostname", `"plesk":true`, map[string]interface{}{"server_id": 1, "active_profile": "plesk", "hostname": "panel.example.com"}, nil},
+		{"plesk without hostname", `"plesk":true`, map[string]interface{}{"server_id": 1, "active_profile": "plesk"},
+			[]string{"hostname: required by the plesk boot profile"}},
+		{"cpanel without hostname or flag", `"cpanel":false`, map[string]interface{}{"server_id": 1, "active_profile": "cpanel"},
+			[]string{"hostname: required by the cpanel boot profile", "active_profile: server 1 does not support the cpanel boot profile"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := planBoot(newBootStandIn(t, tc.flags, nil), tc.config)
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("plan failed: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("plan succeeded, want %v", tc.wantErr)
+			}
+			for _, want := range tc.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBootOptionValues(t *testing.T) {
+	cases := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{"missing", `{}`, []string{}},
+		{"null", `{"dist":null}`, []string{}},
+		{"value in use", `{"dist":"Debian 12 base"}`, []string{"Debian 12 base"}},
+		{"offered values", `{"dist":["Debian 12 base","Ubuntu 24.04 LTS base"]}`, []string{"Debian 12 base", "Ubuntu 24.04 LTS base"}},
+		{"numbers", `{"dist":[64,32]}`, []string{"64", "32"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bootOptionValues(gjson.Get(tc.json, "dist")); strings.Join(got, "|") != strings.Join(tc.want, "|") || got == nil {
+				t.Errorf("bootOptionValues() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceBootCustomizeDiffOptions(t *testing.T) {
+	linuxOffered := `{"server_number":1,"dist":["Debian 12 base","Ubuntu 24.04 LTS base"],"arch":[64,32],"lang":["en","de"],"active":false}`
+
+	cases := []struct {
+		name    string
+		options map[string]string
+		config  map[string]interface{}
+		wantErr []string
+	}{
+		{"offered values", map[string]string{"linux": linuxOffered},
+			map[string]interface{}{"server_id": 1, "active_profile": "linux", "operating_system": "Debian 12 base", "language": "de", "architecture": "32"}, nil},
+		{"unset values", map[string]string{"linux": linuxOffered},
+			map[string]interface{}{"server_id": 1, "active_profile": "linux"}, nil},
+		{"active profile is not checked", map[string]string{"linux": `{"server_number":1,"dist":"Debian 12 base","arch":64,"lang":"en","active":true}`},
+			map[string]interface{}{"server_id": 1, "active_profile": "linux", "operating_system": "Ubuntu 24.04 LTS base", "language": "de"}, nil},
+		{"single distribution offered", map[string]string{"vnc": `{"server_number":1,"dist":"Fedora-40","arch":64,"lang":"en_US","active":false}`},
+			map[string]interface{}{"server_id": 1, "active_profile": "vnc", "operating_system": "Fedora-40", "language": "en_US"}, nil},
+		{"other than the single distribution", map[string]string{"vnc": `{"server_number":1,"dist":"Fedora-40","arch":64,"lang":"en_US","active":false}`},
+			map[string]interface{}{"server_id": 1, "active_profile": "vnc", "operating_system": "Fedora-39"},
+			[]string{`operating_system: "Fedora-39" is not offered by the vnc boot profile of server 1, use one of Fedora-40`}},
+		{"rejected operating system", map[string]string{"linux": linuxOffered},
+			map[string]interface{}{"server_id": 1, "active_profile": "linux", "operating_system": "Arch Linux latest minimal"},
+			[]string{`operating_system: "Arch Linux latest minimal" is not offered by the linux boot profile of server 1, use one of Debian 12 base, Ubuntu 24.04 LTS base`}},
+		{"rejected language and architecture", map[string]string{"linux": linuxOffered},
+			map[string]interface{}{"server_id": 1, "active_profile": "linux", "language": "fr", "architecture": "arm64"},
+			[]string{
+				`language: "fr" is not offered by the linux boot profile of server 1, use one of en, de`,
+				`architecture: "arm64" is not offered by the linux boot profile of server 1, use one of 64, 32`,
+			}},
+		{"rescue checks os", map[string]string{"rescue": `{"server_number":1,"os":["linux","vkvm"],"arch":[64],"active":false}`},
+			map[string]interface{}{"server_id": 1, "active_profile": "rescue", "operating_system": "freebsd"},
+			[]string{`operating_system: "freebsd" is not offered by the rescue boot profile of server 1, use one of linux, vkvm`}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := planBoot(newBootStandIn(t, `"rescue":true,"vnc":true`, tc.options), tc.config)
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("plan failed: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("plan succeeded, want %v", tc.wantErr)
+			}
+			for _, want := range tc.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
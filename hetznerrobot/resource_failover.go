@@ -0,0 +1,128 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// failoverAttributePaths maps failover request parameters to resource attributes.
+var failoverAttributePaths = attributePaths(map[string]string{
+	"active_server_ip": "active_server_ip",
+})
+
+func resourceFailover() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFailoverCreate,
+		ReadContext:   resourceFailoverRead,
+		UpdateContext: resourceFailoverUpdate,
+		DeleteContext: resourceFailoverDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"ip": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				Description:      "Failover IP address or IPv6 net",
+			},
+			"active_server_ip": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				Description:      "Main IP of the server the failover IP is routed to",
+			},
+			// read-only / computed
+			"netmask": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Failover netmask",
+			},
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Main IP of the server the failover IP belongs to",
+			},
+			"server_ipv6": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server IPv6 Net of the server the failover IP belongs to",
+			},
+			"server_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of the server the failover IP belongs to",
+			},
+		},
+	}
+}
+
+func resourceFailoverCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Get("ip").(string)
+	failover, err := c.routeFailover(ctx, ip, d.Get("active_server_ip").(string))
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to route failover IP %s", ip), failoverAttributePaths)
+	}
+
+	d.SetId(failover.IP)
+
+	return resourceFailoverRead(ctx, d, meta)
+}
+
+func resourceFailoverRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	failover, err := c.getFailover(ctx, ip)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "failover IP not found, removing from state", map[string]interface{}{
+				"ip": ip,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find failover IP %s:\n\t %q", ip, err)
+	}
+
+	d.Set("ip", failover.IP)
+	d.Set("active_server_ip", failover.ActiveServerIP)
+	d.Set("netmask", failover.Netmask)
+	d.Set("server_ip", failover.ServerIP)
+	d.Set("server_ipv6", failover.ServerIPv6)
+	d.Set("server_number", failover.ServerNumber)
+
+	return nil
+}
+
+func resourceFailoverUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	if _, err := c.routeFailover(ctx, ip, d.Get("active_server_ip").(string)); err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to route failover IP %s", ip), failoverAttributePaths)
+	}
+
+	return resourceFailoverRead(ctx, d, meta)
+}
+
+func resourceFailoverDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	if err := c.unrouteFailover(ctx, ip); err != nil && !IsNotFound(err) {
+		return diag.Errorf("Unable to unroute failover IP %s:\n\t %q", ip, err)
+	}
+
+	return nil
+}
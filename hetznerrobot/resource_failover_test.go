@@ -0,0 +1,73 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testFailover = `{"failover":{"ip":"198.51.100.1","netmask":"255.255.255.255","server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","server_number":1,"active_server_ip":"192.0.2.2"}}`
+
+func TestResourceFailoverCreate(t *testing.T) {
+	cases := []struct {
+		name  string
+		route string
+	}{
+		{"routed", testFailover},
+		{"already routed", `{"error":{"status":409,"code":"FAILOVER_ALREADY_ROUTED","message":"The failover IP is already routed to the selected server"}}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			robot, c := newRobotAPIStandIn(t, map[string]string{
+				"POST /failover/198.51.100.1": tc.route,
+				"GET /failover/198.51.100.1":  testFailover,
+			})
+			d := schema.TestResourceDataRaw(t, resourceFailover().Schema, map[string]interface{}{"ip": "198.51.100.1", "active_server_ip": "192.0.2.2"})
+			if diags := resourceFailoverCreate(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("create failed: %v", diags)
+			}
+			robot.checkWrites(t, "POST /failover/198.51.100.1 active_server_ip=192.0.2.2")
+			if d.Id() != "198.51.100.1" || d.Get("server_number").(int) != 1 || d.Get("netmask").(string) != "255.255.255.255" {
+				t.Errorf("state = %v, want the failover read back", d.State())
+			}
+		})
+	}
+}
+
+func TestResourceFailoverCreateInvalidServer(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /failover/198.51.100.1": `{"error":{"status":400,"code":"INVALID_INPUT","message":"invalid input","missing":null,"invalid":["active_server_ip"]}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceFailover().Schema, map[string]interface{}{"ip": "198.51.100.1", "active_server_ip": "203.0.113.1"})
+	diags := resourceFailoverCreate(context.Background(), d, c)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("active_server_ip")) {
+		t.Errorf("diagnostics = %v, want one at active_server_ip", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID = %q, want none", d.Id())
+	}
+}
+
+func TestResourceFailoverDelete(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"DELETE /failover/198.51.100.1": `{"failover":{"ip":"198.51.100.1","netmask":"255.255.255.255","server_ip":"192.0.2.1","server_number":1,"active_server_ip":null}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceFailover().Schema, map[string]interface{}{"ip": "198.51.100.1", "active_server_ip": "192.0.2.2"})
+	d.SetId("198.51.100.1")
+	if diags := resourceFailoverDelete(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	robot.checkWrites(t, "DELETE /failover/198.51.100.1")
+}
+
+func TestGetFailoversWithoutFailoverIPs(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /failover": `{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`,
+	})
+	failovers, err := c.getFailovers(context.Background())
+	if err != nil || len(failovers) != 0 {
+		t.Errorf("failovers = %+v, %v, want none", failovers, err)
+	}
+}
@@ -2,22 +2,43 @@ package hetznerrobot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const (
+	firewallDeleteBehaviorDisable    = "disable"
+	firewallDeleteBehaviorClearRules = "clear_rules"
+	firewallDeleteBehaviorDelete     = "delete"
+	firewallDeleteBehaviorKeep       = "keep"
+)
+
 func resourceFirewall() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFirewallCreate,
 		ReadContext:   resourceFirewallRead,
 		UpdateContext: resourceFirewallUpdate,
 		DeleteContext: resourceFirewallDelete,
+		CustomizeDiff: resourceFirewallCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceFirewallImportState,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"server_ip": {
 				Type:     schema.TypeString,
@@ -29,58 +50,267 @@ func resourceFirewall() *schema.Resource {
 			},
 			"whitelist_hos": {
 				Type:     schema.TypeBool,
-				Required: true,
+				Optional: true,
+				Default:  false,
+			},
+			"template_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"rule", "output_rule"},
+				Description:   "ID of a firewall template to apply; its rules, whitelist_hos and filter_ipv6 settings replace the ones of this resource",
+			},
+			"filter_ipv6": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Apply the firewall to IPv6 traffic as well",
 			},
 			"rule": {
-				Type:     schema.TypeList,
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Rules for incoming traffic",
+				Elem:        firewallRuleResource(),
+			},
+			"output_rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Rules for outgoing traffic",
+				Elem:        firewallRuleResource(),
+			},
+			"delete_behavior": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  firewallDeleteBehaviorDisable,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					firewallDeleteBehaviorDisable,
+					firewallDeleteBehaviorClearRules,
+					firewallDeleteBehaviorDelete,
+					firewallDeleteBehaviorKeep,
+				}, false)),
+				Description: "What happens to the firewall on destroy: disable it keeping its rules (default), " +
+					"clear_rules to remove all rules and disable it, delete to reset the whole configuration, or keep it unchanged. " +
+					"An active firewall without rules discards all incoming traffic, so clear_rules disables it as well",
+			},
+		},
+	}
+}
+
+func firewallRuleResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ip_version": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressInferredIPVersion,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"",
+					"ipv4",
+					"ipv6",
+				}, false)),
+				Description: "IP version the rule applies to (ipv4 or ipv6); when empty it is derived from src_ip/dst_ip, otherwise the rule applies to both",
+			},
+			"dst_ip": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: firewallRuleFieldValidator(validateFirewallAddressSyntax),
+			},
+			"dst_port": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: firewallRuleFieldValidator(validateFirewallPort),
+			},
+			"src_ip": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: firewallRuleFieldValidator(validateFirewallAddressSyntax),
+			},
+			"src_port": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: firewallRuleFieldValidator(validateFirewallPort),
+			},
+			"protocol": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: firewallRuleFieldValidator(validateFirewallProtocol),
+			},
+			"tcp_flags": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: firewallRuleFieldValidator(validateFirewallTCPFlags),
+			},
+			"action": {
+				Type: schema.TypeString,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					"accept",
+					"discard",
+				}, false)),
 				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"dst_ip": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"dst_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"src_ip": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"src_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"protocol": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"tcp_flags": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"action": {
-							Type: schema.TypeString,
-							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
-								"accept",
-								"discard",
-							}, false)),
-							Required: true,
-						},
-					},
-				},
 			},
 		},
 	}
 }
 
+// suppressInferredIPVersion hides the ip_version Robot reports for rules that
+// leave it empty but whose addresses imply it, see expandFirewallRules.
+func suppressInferredIPVersion(k, old, new string, d *schema.ResourceData) bool {
+	if new != "" {
+		return false
+	}
+	rule := strings.TrimSuffix(k, "ip_version")
+	return old == inferFirewallIPVersion(d.Get(rule+"src_ip").(string), d.Get(rule+"dst_ip").(string))
+}
+
+func expandFirewallRules(list []interface{}) []HetznerRobotFirewallRule {
+	rules := make([]HetznerRobotFirewallRule, 0, len(list))
+	for _, ruleMap := range list {
+		ruleProperties := ruleMap.(map[string]interface{})
+		ipVersion := ruleProperties["ip_version"].(string)
+		if ipVersion == "" {
+			// Robot only accepts addresses in rules restricted to one IP version
+			ipVersion = inferFirewallIPVersion(ruleProperties["src_ip"].(string), ruleProperties["dst_ip"].(string))
+		}
+		rules = append(rules, HetznerRobotFirewallRule{
+			Name:      ruleProperties["name"].(string),
+			IPVersion: ipVersion,
+			SrcIP:     ruleProperties["src_ip"].(string),
+			SrcPort:   ruleProperties["src_port"].(string),
+			DstIP:     ruleProperties["dst_ip"].(string),
+			DstPort:   ruleProperties["dst_port"].(string),
+			Protocol:  ruleProperties["protocol"].(string),
+			TCPFlags:  ruleProperties["tcp_flags"].(string),
+			Action:    ruleProperties["action"].(string),
+		})
+	}
+	return rules
+}
+
+func flattenFirewallRules(firewallRules []HetznerRobotFirewallRule) []map[string]interface{} {
+	rules := make([]map[string]interface{}, 0, len(firewallRules))
+	for _, rule := range firewallRules {
+		rules = append(rules, map[string]interface{}{
+			"name":       rule.Name,
+			"ip_version": rule.IPVersion,
+			"src_ip":     rule.SrcIP,
+			"src_port":   rule.SrcPort,
+			"dst_ip":     rule.DstIP,
+			"dst_port":   rule.DstPort,
+			"protocol":   rule.Protocol,
+			"tcp_flags":  rule.TCPFlags,
+			"action":     rule.Action,
+		})
+	}
+	return rules
+}
+
+var firewallRuleParameter = regexp.MustCompile(`^rules\[(input|output)\]\[(\d+)\]\[(\w+)\]$`)
+
+// firewallAttributePath maps firewall request parameters, including the
+// indexed rules[input|output][N][field] ones, to resource attributes.
+func firewallAttributePath(field string) cty.Path {
+	if m := firewallRuleParameter.FindStringSubmatch(field); m != nil {
+		block := "rule"
+		if m[1] == "output" {
+			block = "output_rule"
+		}
+		idx, _ := strconv.Atoi(m[2])
+		return cty.GetAttrPath(block).IndexInt(idx).GetAttr(m[3])
+	}
+	switch field {
+	case "filter_ipv6":
+		return cty.GetAttrPath("filter_ipv6")
+	case "status":
+		return cty.GetAttrPath("active")
+	case "whitelist_hos":
+		return cty.GetAttrPath("whitelist_hos")
+	}
+	return nil
+}
+
+// applyFirewall posts the firewall configuration and waits until Robot has
+// applied it. A change still in process from an earlier apply is awaited
+// before the configuration is posted again.
+func applyFirewall(ctx context.Context, c *HetznerRobotClient, firewall HetznerRobotFirewall, timeout time.Duration) diag.Diagnostics {
+	deadline := time.Now().Add(timeout)
+
+	err := c.setFirewall(ctx, firewall)
+	if IsInProcess(err) {
+		tflog.Info(ctx, "previous firewall change still in process, waiting before applying", map[string]interface{}{
+			"server_ip": firewall.IP,
+		})
+		if _, diags := waitForFirewall(ctx, c, firewall.IP, time.Until(deadline)); diags.HasError() {
+			return diags
+		}
+		err = c.setFirewall(ctx, firewall)
+	}
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to set firewall for server %s", firewall.IP), firewallAttributePath)
+	}
+
+	_, diags := waitForFirewall(ctx, c, firewall.IP, time.Until(deadline))
+	return diags
+}
+
+// waitForFirewall polls the firewall until Robot has finished applying the
+// last change, i.e. the status is no longer "in process".
+func waitForFirewall(ctx context.Context, c *HetznerRobotClient, serverIP string, timeout time.Duration) (*HetznerRobotFirewall, diag.Diagnostics) {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{firewallStatusInProcess},
+		Target:  []string{firewallStatusActive, firewallStatusDisabled},
+		Refresh: func() (interface{}, string, error) {
+			firewall, err := c.getFirewall(ctx, serverIP)
+			if err != nil {
+				return nil, "", err
+			}
+			return firewall, firewall.Status, nil
+		},
+		Timeout:    timeout,
+		Delay:      5 * time.Second,
+		MinTimeout: 5 * time.Second,
+	}
+
+	result, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		var timeoutErr *retry.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return nil, diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Timed out waiting for firewall of server %s to be applied", serverIP),
+				Detail: fmt.Sprintf("The firewall is still %q after %s. Robot applies firewall changes asynchronously; "+
+					"increase the timeouts of the resource if this server regularly takes longer.", timeoutErr.LastState, timeout),
+			}}
+		}
+		return nil, diag.Errorf("Unable to wait for firewall of server %s:\n\t %q", serverIP, err)
+	}
+
+	return result.(*HetznerRobotFirewall), nil
+}
+
+func firewallFromResourceData(d *schema.ResourceData) HetznerRobotFirewall {
+	status := firewallStatusDisabled
+	if d.Get("active").(bool) {
+		status = firewallStatusActive
+	}
+
+	return HetznerRobotFirewall{
+		IP:                       d.Get("server_ip").(string),
+		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
+		FilterIPv6:               d.Get("filter_ipv6").(bool),
+		TemplateID:               d.Get("template_id").(int),
+		Status:                   status,
+		Rules: HetznerRobotFirewallRules{
+			Input:  expandFirewallRules(d.Get("rule").([]interface{})),
+			Output: expandFirewallRules(d.Get("output_rule").([]interface{})),
+		},
+	}
+}
+
 func resourceFirewallImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(HetznerRobotClient)
+	c := m.(*HetznerRobotClient)
 
 	firewallID := d.Id()
 
@@ -94,25 +324,13 @@ func resourceFirewallImportState(ctx context.Context, d *schema.ResourceData, m
 		active = true
 	}
 
-	rules := make([]map[string]interface{}, 0)
-	for _, rule := range firewall.Rules.Input {
-		r := map[string]interface{}{
-			"name":      rule.Name,
-			"src_ip":    rule.SrcIP,
-			"src_port":  rule.SrcPort,
-			"dst_ip":    rule.DstIP,
-			"dst_port":  rule.DstPort,
-			"protocol":  rule.Protocol,
-			"tcp_flags": rule.TCPFlags,
-			"action":    rule.Action,
-		}
-		rules = append(rules, r)
-	}
-
 	d.Set("active", active)
-	d.Set("rule", rules)
+	d.Set("filter_ipv6", firewall.FilterIPv6)
+	d.Set("rule", flattenFirewallRules(firewall.Rules.Input))
+	d.Set("output_rule", flattenFirewallRules(firewall.Rules.Output))
 	d.Set("server_ip", firewall.IP)
 	d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
+	d.Set("delete_behavior", firewallDeleteBehaviorDisable)
 	d.SetId(firewall.IP)
 
 	results := make([]*schema.ResourceData, 1)
@@ -121,37 +339,12 @@ func resourceFirewallImportState(ctx context.Context, d *schema.ResourceData, m
 }
 
 func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(HetznerRobotClient)
+	c := m.(*HetznerRobotClient)
 
 	serverIP := d.Get("server_ip").(string)
 
-	status := "disabled"
-	if d.Get("active").(bool) {
-		status = "active"
-	}
-
-	rules := make([]HetznerRobotFirewallRule, 0)
-	for _, ruleMap := range d.Get("rule").([]interface{}) {
-		ruleProperties := ruleMap.(map[string]interface{})
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:     ruleProperties["name"].(string),
-			SrcIP:    ruleProperties["src_ip"].(string),
-			SrcPort:  ruleProperties["src_port"].(string),
-			DstIP:    ruleProperties["dst_ip"].(string),
-			DstPort:  ruleProperties["dst_port"].(string),
-			Protocol: ruleProperties["protocol"].(string),
-			TCPFlags: ruleProperties["tcp_flags"].(string),
-			Action:   ruleProperties["action"].(string),
-		})
-	}
-
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
-	}); err != nil {
-		return diag.FromErr(err)
+	if diags := applyFirewall(ctx, c, firewallFromResourceData(d), d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+		return diags
 	}
 
 	d.SetId(serverIP)
@@ -163,12 +356,19 @@ func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m inter
 }
 
 func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(HetznerRobotClient)
+	c := m.(*HetznerRobotClient)
 
 	serverIP := d.Id()
 
 	firewall, err := c.getFirewall(ctx, serverIP)
 	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "firewall not found, removing from state", map[string]interface{}{
+				"server_ip": serverIP,
+			})
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(err)
 	}
 
@@ -177,24 +377,15 @@ func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interfa
 		active = true
 	}
 
-	rules := make([]map[string]interface{}, 0)
-	for _, rule := range firewall.Rules.Input {
-		r := map[string]interface{}{
-			"name":      rule.Name,
-			"src_ip":    rule.SrcIP,
-			"src_port":  rule.SrcPort,
-			"dst_ip":    rule.DstIP,
-			"dst_port":  rule.DstPort,
-			"protocol":  rule.Protocol,
-			"tcp_flags": rule.TCPFlags,
-			"action":    rule.Action,
-		}
-		rules = append(rules, r)
-	}
 	d.Set("active", active)
-	d.Set("rule", rules)
 	d.Set("server_ip", firewall.IP)
-	d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
+	// settings of an applied template are owned by hetzner-robot_firewall_template
+	if d.Get("template_id").(int) == 0 {
+		d.Set("filter_ipv6", firewall.FilterIPv6)
+		d.Set("rule", flattenFirewallRules(firewall.Rules.Input))
+		d.Set("output_rule", flattenFirewallRules(firewall.Rules.Output))
+		d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -203,37 +394,10 @@ func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(HetznerRobotClient)
-
-	serverIP := d.Get("server_ip").(string)
+	c := m.(*HetznerRobotClient)
 
-	status := "disabled"
-	if d.Get("active").(bool) {
-		status = "active"
-	}
-
-	rules := make([]HetznerRobotFirewallRule, 0)
-	for _, ruleMap := range d.Get("rule").([]interface{}) {
-		ruleProperties := ruleMap.(map[string]interface{})
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:     ruleProperties["name"].(string),
-			SrcIP:    ruleProperties["src_ip"].(string),
-			SrcPort:  ruleProperties["src_port"].(string),
-			DstIP:    ruleProperties["dst_ip"].(string),
-			DstPort:  ruleProperties["dst_port"].(string),
-			Protocol: ruleProperties["protocol"].(string),
-			TCPFlags: ruleProperties["tcp_flags"].(string),
-			Action:   ruleProperties["action"].(string),
-		})
-	}
-
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
-	}); err != nil {
-		return diag.FromErr(err)
+	if diags := applyFirewall(ctx, c, firewallFromResourceData(d), d.Timeout(schema.TimeoutUpdate)); diags.HasError() {
+		return diags
 	}
 
 	// Warning or errors can be collected in a slice type
@@ -243,8 +407,46 @@ func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m inter
 }
 
 func resourceFirewallDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	c := m.(*HetznerRobotClient)
+
+	serverIP := d.Get("server_ip").(string)
+	timeout := d.Timeout(schema.TimeoutDelete)
+
+	switch d.Get("delete_behavior").(string) {
+	case firewallDeleteBehaviorKeep:
+		tflog.Info(ctx, "keeping firewall configuration on destroy", map[string]interface{}{
+			"server_ip": serverIP,
+		})
+		return nil
+	case firewallDeleteBehaviorDisable:
+		firewall := firewallFromResourceData(d)
+		firewall.Status = firewallStatusDisabled
+		return applyFirewall(ctx, c, firewall, timeout)
+	case firewallDeleteBehaviorClearRules:
+		// Robot discards traffic no rule accepts, an active firewall without
+		// rules would cut the server off
+		firewall := firewallFromResourceData(d)
+		firewall.TemplateID = 0
+		firewall.Status = firewallStatusDisabled
+		firewall.Rules = HetznerRobotFirewallRules{}
+		return applyFirewall(ctx, c, firewall, timeout)
+	}
+
+	deadline := time.Now().Add(timeout)
+	err := c.deleteFirewall(ctx, serverIP)
+	if IsInProcess(err) {
+		if _, diags := waitForFirewall(ctx, c, serverIP, time.Until(deadline)); diags.HasError() {
+			return diags
+		}
+		err = c.deleteFirewall(ctx, serverIP)
+	}
+	if err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return diag.Errorf("Unable to delete firewall of server %s:\n\t %q", serverIP, err)
+	}
 
+	_, diags := waitForFirewall(ctx, c, serverIP, time.Until(deadline))
 	return diags
 }
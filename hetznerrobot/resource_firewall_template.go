@@ -0,0 +1,137 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceFirewallTemplate() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFirewallTemplateCreate,
+		ReadContext:   resourceFirewallTemplateRead,
+		UpdateContext: resourceFirewallTemplateUpdate,
+		DeleteContext: resourceFirewallTemplateDelete,
+		CustomizeDiff: resourceFirewallCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Template name",
+			},
+			"is_default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Use the template as default when ordering new servers",
+			},
+			"whitelist_hos": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whitelist Hetzner services",
+			},
+			"filter_ipv6": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Apply the firewall to IPv6 traffic as well",
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Rules for incoming traffic",
+				Elem:        firewallRuleResource(),
+			},
+			"output_rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Rules for outgoing traffic",
+				Elem:        firewallRuleResource(),
+			},
+		},
+	}
+}
+
+func firewallTemplateFromResourceData(d *schema.ResourceData) HetznerRobotFirewallTemplate {
+	return HetznerRobotFirewallTemplate{
+		Name:                     d.Get("name").(string),
+		IsDefault:                d.Get("is_default").(bool),
+		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
+		FilterIPv6:               d.Get("filter_ipv6").(bool),
+		Rules: HetznerRobotFirewallRules{
+			Input:  expandFirewallRules(d.Get("rule").([]interface{})),
+			Output: expandFirewallRules(d.Get("output_rule").([]interface{})),
+		},
+	}
+}
+
+func resourceFirewallTemplateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	template, err := c.createFirewallTemplate(ctx, firewallTemplateFromResourceData(d))
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to create firewall template %q", d.Get("name").(string)), firewallAttributePath)
+	}
+
+	d.SetId(strconv.Itoa(template.ID))
+
+	return resourceFirewallTemplateRead(ctx, d, meta)
+}
+
+func resourceFirewallTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	templateID := d.Id()
+	template, err := c.getFirewallTemplate(ctx, templateID)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "firewall template not found, removing from state", map[string]interface{}{
+				"id": templateID,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find firewall template with ID %s:\n\t %q", templateID, err)
+	}
+
+	d.Set("name", template.Name)
+	d.Set("is_default", template.IsDefault)
+	d.Set("whitelist_hos", template.WhitelistHetznerServices)
+	d.Set("filter_ipv6", template.FilterIPv6)
+	d.Set("rule", flattenFirewallRules(template.Rules.Input))
+	d.Set("output_rule", flattenFirewallRules(template.Rules.Output))
+
+	return nil
+}
+
+func resourceFirewallTemplateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	templateID := d.Id()
+	if _, err := c.updateFirewallTemplate(ctx, templateID, firewallTemplateFromResourceData(d)); err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to update firewall template %s", templateID), firewallAttributePath)
+	}
+
+	return resourceFirewallTemplateRead(ctx, d, meta)
+}
+
+func resourceFirewallTemplateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	templateID := d.Id()
+	if err := c.deleteFirewallTemplate(ctx, templateID); err != nil && !IsNotFound(err) {
+		return diag.Errorf("Unable to delete firewall template %s:\n\t %q", templateID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testFirewallTemplate = `{"firewall_template":{"id":1234,"name":"web","filter_ipv6":false,"whitelist_hos":true,"is_default":false,"rules":{` +
+	`"input":[{"ip_version":"ipv4","name":"http","dst_ip":null,"src_ip":null,"dst_port":"80","src_port":null,"protocol":"tcp","tcp_flags":null,"action":"accept"}],"output":[]}}}`
+
+func TestResourceFirewallTemplateCreate(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /firewall/template":     testFirewallTemplate,
+		"GET /firewall/template/1234": testFirewallTemplate,
+	})
+	d := schema.TestResourceDataRaw(t, resourceFirewallTemplate().Schema, map[string]interface{}{
+		"name":          "web",
+		"whitelist_hos": true,
+		"rule": []interface{}{map[string]interface{}{
+			"name": "http", "ip_version": "ipv4", "dst_port": "80", "protocol": "tcp", "action": "accept",
+		}},
+	})
+	if diags := resourceFirewallTemplateCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	robot.checkWrites(t, "POST /firewall/template filter_ipv6=false&is_default=false&name=web"+
+		"&rules%5Binput%5D%5B0%5D%5Baction%5D=accept&rules%5Binput%5D%5B0%5D%5Bdst_port%5D=80&rules%5Binput%5D%5B0%5D%5Bip_version%5D=ipv4"+
+		"&rules%5Binput%5D%5B0%5D%5Bname%5D=http&rules%5Binput%5D%5B0%5D%5Bprotocol%5D=tcp&whitelist_hos=true")
+	if d.Id() != "1234" || d.Get("rule.0.dst_port").(string) != "80" {
+		t.Errorf("state = %v, want the template read back", d.State())
+	}
+}
+
+func TestResourceFirewallTemplateDelete(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, nil)
+	d := schema.TestResourceDataRaw(t, resourceFirewallTemplate().Schema, map[string]interface{}{"name": "web"})
+	d.SetId("1234")
+	// a template removed outside of Terraform is already gone
+	if diags := resourceFirewallTemplateDelete(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("delete failed: %v", diags)
+	}
+	robot.checkWrites(t, "DELETE /firewall/template/1234")
+}
+
+func TestSetFirewallTemplate(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{"POST /firewall/192.0.2.1": testFirewall(firewallStatusActive)})
+	err := c.setFirewall(context.Background(), HetznerRobotFirewall{
+		IP:         "192.0.2.1",
+		Status:     firewallStatusActive,
+		TemplateID: 1234,
+		Rules:      HetznerRobotFirewallRules{Input: []HetznerRobotFirewallRule{{Name: "ignored", Action: "accept"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the template replaces all other settings
+	robot.checkWrites(t, "POST /firewall/192.0.2.1 status=active&template_id=1234")
+}
+
+func TestResourceFirewallReadWithTemplate(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{"GET /firewall/192.0.2.1": testFirewall(firewallStatusActive)})
+	d := schema.TestResourceDataRaw(t, resourceFirewall().Schema, map[string]interface{}{"server_ip": "192.0.2.1", "template_id": 1234, "whitelist_hos": false})
+	d.SetId("192.0.2.1")
+	if diags := resourceFirewallRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if !d.Get("active").(bool) || d.Get("whitelist_hos").(bool) {
+		t.Errorf("state = %v, want the status read and the template's settings left alone", d.State())
+	}
+}
@@ -0,0 +1,160 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testFirewallInProcess = `{"error":{"status":409,"code":"FIREWALL_IN_PROCESS","message":"The firewall cannot be updated because a previous change is still in process"}}`
+
+func testFirewall(status string) string {
+	return `{"firewall":{"server_ip":"192.0.2.1","server_number":1,"status":"` + status + `","filter_ipv6":false,"whitelist_hos":true,"port":"main","rules":{"input":[],"output":[]}}}`
+}
+
+func TestApplyFirewall(t *testing.T) {
+	cases := []struct {
+		name      string
+		post      string
+		get       string
+		timeout   time.Duration
+		wantPosts int
+		wantErr   string
+	}{
+		{"applied", testFirewall(firewallStatusActive), testFirewall(firewallStatusActive), time.Minute, 1, ""},
+		{"previous change in process", testFirewallInProcess, testFirewall(firewallStatusActive), time.Minute, 2, "Unable to set firewall for server 192.0.2.1"},
+		{"not applied in time", testFirewall(firewallStatusInProcess), testFirewall(firewallStatusInProcess), time.Second, 1, "Timed out waiting for firewall of server 192.0.2.1 to be applied"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Robot is polled every 5 seconds
+			t.Parallel()
+			robot, c := newRobotAPIStandIn(t, map[string]string{
+				"POST /firewall/192.0.2.1": tc.post,
+				"GET /firewall/192.0.2.1":  tc.get,
+			})
+			diags := applyFirewall(context.Background(), c, HetznerRobotFirewall{IP: "192.0.2.1", Status: firewallStatusActive}, tc.timeout)
+			if got := len(robot.writes()); got != tc.wantPosts {
+				t.Errorf("posted %d times, want %d", got, tc.wantPosts)
+			}
+			if tc.wantErr == "" {
+				if diags.HasError() {
+					t.Errorf("apply failed: %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() || !strings.HasPrefix(diags[0].Summary, tc.wantErr) {
+				t.Errorf("diagnostics = %v, want %q", diags, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetFirewallRules(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{"POST /firewall/192.0.2.1": testFirewall(firewallStatusActive)})
+	err := c.setFirewall(context.Background(), HetznerRobotFirewall{
+		IP:         "192.0.2.1",
+		Status:     firewallStatusActive,
+		FilterIPv6: true,
+		Rules: HetznerRobotFirewallRules{
+			Input: []HetznerRobotFirewallRule{
+				{Name: "ssh", IPVersion: "ipv6", SrcIP: "2001:db8::/32", DstPort: "22", Protocol: "tcp", Action: "accept"},
+				{Name: "drop", Action: "discard"},
+			},
+			Output: []HetznerRobotFirewallRule{{Name: "all", Action: "accept"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writes := robot.writes()
+	if len(writes) != 1 {
+		t.Fatalf("requests = %v, want one POST", writes)
+	}
+	form, _ := url.QueryUnescape(strings.TrimPrefix(writes[0], "POST /firewall/192.0.2.1 "))
+	want := "filter_ipv6=true&" +
+		"rules[input][0][action]=accept&rules[input][0][dst_port]=22&rules[input][0][ip_version]=ipv6&rules[input][0][name]=ssh&" +
+		"rules[input][0][protocol]=tcp&rules[input][0][src_ip]=2001:db8::/32&" +
+		"rules[input][1][action]=discard&rules[input][1][name]=drop&" +
+		"rules[output][0][action]=accept&rules[output][0][name]=all&" +
+		"status=active&whitelist_hos=false"
+	if form != want {
+		t.Errorf("form =\n\t%s\nwant\n\t%s", form, want)
+	}
+}
+
+func TestResourceFirewallReadRules(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /firewall/192.0.2.1": `{"firewall":{"server_ip":"192.0.2.1","server_number":1,"status":"active","filter_ipv6":true,"whitelist_hos":true,"port":"main","rules":{` +
+			`"input":[{"ip_version":"ipv6","name":"ssh","dst_ip":null,"src_ip":"2001:db8::/32","dst_port":"22","src_port":null,"protocol":"tcp","tcp_flags":null,"action":"accept"}],` +
+			`"output":[{"ip_version":null,"name":"all","dst_ip":null,"src_ip":null,"dst_port":null,"src_port":null,"protocol":null,"tcp_flags":null,"action":"accept"}]}}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceFirewall().Schema, map[string]interface{}{"server_ip": "192.0.2.1"})
+	d.SetId("192.0.2.1")
+	if diags := resourceFirewallRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if !d.Get("filter_ipv6").(bool) || d.Get("rule.0.ip_version").(string) != "ipv6" || d.Get("rule.0.src_ip").(string) != "2001:db8::/32" ||
+		d.Get("output_rule.#").(int) != 1 || d.Get("output_rule.0.action").(string) != "accept" {
+		t.Errorf("state = %v, want both rule directions", d.State())
+	}
+}
+
+func TestResourceFirewallDelete(t *testing.T) {
+	cases := []struct {
+		behavior string
+		want     string
+	}{
+		{firewallDeleteBehaviorKeep, ""},
+		{firewallDeleteBehaviorDisable, "POST /firewall/192.0.2.1 filter_ipv6=false&rules[input][0][action]=accept&rules[input][0][name]=ssh&status=disabled&whitelist_hos=true"},
+		// an active firewall without rules would discard all traffic
+		{firewallDeleteBehaviorClearRules, "POST /firewall/192.0.2.1 filter_ipv6=false&status=disabled&whitelist_hos=true"},
+		{firewallDeleteBehaviorDelete, "DELETE /firewall/192.0.2.1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.behavior, func(t *testing.T) {
+			t.Parallel()
+			robot, c := newRobotAPIStandIn(t, map[string]string{
+				"POST /firewall/192.0.2.1":   testFirewall(firewallStatusDisabled),
+				"DELETE /firewall/192.0.2.1": testFirewall(firewallStatusDisabled),
+				"GET /firewall/192.0.2.1":    testFirewall(firewallStatusDisabled),
+			})
+			d := schema.TestResourceDataRaw(t, resourceFirewall().Schema, map[string]interface{}{
+				"server_ip":       "192.0.2.1",
+				"active":          true,
+				"whitelist_hos":   true,
+				"delete_behavior": tc.behavior,
+				"rule":            []interface{}{map[string]interface{}{"name": "ssh", "action": "accept"}},
+			})
+			d.SetId("192.0.2.1")
+			if diags := resourceFirewallDelete(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("delete failed: %v", diags)
+			}
+			var got []string
+			for _, request := range robot.writes() {
+				request, _ = url.QueryUnescape(request)
+				got = append(got, request)
+			}
+			if strings.Join(got, "\n") != tc.want {
+				t.Errorf("requests = %v, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestResourceFirewallImportState(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{"GET /firewall/192.0.2.1": testFirewall(firewallStatusActive)})
+	d := schema.TestResourceDataRaw(t, resourceFirewall().Schema, map[string]interface{}{})
+	d.SetId("192.0.2.1")
+	if _, err := resourceFirewallImportState(context.Background(), d, c); err != nil {
+		t.Fatal(err)
+	}
+	if d.Get("delete_behavior").(string) != firewallDeleteBehaviorDisable || d.Get("server_ip").(string) != "192.0.2.1" {
+		t.Errorf("state = %v, want the default delete_behavior", d.State())
+	}
+}
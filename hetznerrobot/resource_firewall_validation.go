@@ -0,0 +1,196 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// firewallMaxRules is the number of rules Robot accepts per direction.
+const firewallMaxRules = 10
+
+var firewallProtocols = []string{"tcp", "udp", "gre", "icmp", "ipip", "ah", "esp"}
+
+var (
+	firewallPortPattern     = regexp.MustCompile(`^(\d{1,5})(?:-(\d{1,5}))?$`)
+	firewallTCPFlagsPattern = regexp.MustCompile(`^(syn|fin|rst|psh|urg|ack)([|&](syn|fin|rst|psh|urg|ack))*$`)
+)
+
+// resourceFirewallCustomizeDiff rejects rules Robot would refuse, so mistakes
+// show up in terraform plan instead of half-way through an apply. Single
+// fields are checked by the ValidateDiagFuncs of firewallRuleResource, this
+// only covers the rule count and checks spanning several fields.
+func resourceFirewallCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	var errs []error
+	for _, block := range []string{"rule", "output_rule"} {
+		if !d.NewValueKnown(block) {
+			continue
+		}
+		errs = append(errs, validateFirewallRules(d, block)...)
+	}
+	return errors.Join(errs...)
+}
+
+func validateFirewallRules(d *schema.ResourceDiff, block string) []error {
+	var errs []error
+
+	rules := d.Get(block).([]interface{})
+	if len(rules) > firewallMaxRules {
+		errs = append(errs, fmt.Errorf("%s: Robot accepts at most %d rules per direction, got %d", block, firewallMaxRules, len(rules)))
+	}
+
+	for idx, ruleMap := range rules {
+		rule := ruleMap.(map[string]interface{})
+		path := fmt.Sprintf("%s.%d", block, idx)
+		known := func(field string) bool {
+			return d.NewValueKnown(fmt.Sprintf("%s.%s", path, field))
+		}
+
+		if known("src_ip") && known("dst_ip") && known("ip_version") {
+			ipVersion := rule["ip_version"].(string)
+			for _, field := range []string{"src_ip", "dst_ip"} {
+				address := rule[field].(string)
+				// malformed addresses are reported by the field validation
+				if inferFirewallIPVersion(address) == "" {
+					continue
+				}
+				if err := validateFirewallAddress(address, ipVersion); err != nil {
+					errs = append(errs, fmt.Errorf("%s.%s: %w", path, field, err))
+				}
+			}
+			if ipVersion == "" && inferFirewallIPVersion(rule["src_ip"].(string), rule["dst_ip"].(string)) == "" &&
+				inferFirewallIPVersion(rule["src_ip"].(string)) != "" && inferFirewallIPVersion(rule["dst_ip"].(string)) != "" {
+				errs = append(errs, fmt.Errorf("%s: src_ip and dst_ip must be of the same IP version", path))
+			}
+		}
+
+		if known("tcp_flags") && known("protocol") && rule["tcp_flags"].(string) != "" && rule["protocol"].(string) != "tcp" {
+			errs = append(errs, fmt.Errorf("%s.tcp_flags: tcp_flags can only be used with protocol \"tcp\"", path))
+		}
+	}
+
+	return errs
+}
+
+// firewallRuleFieldValidator adapts a check of a single rule field to a
+// ValidateDiagFunc, so Terraform reports the error at the field.
+func firewallRuleFieldValidator(check func(string) error) schema.SchemaValidateDiagFunc {
+	return func(v interface{}, path cty.Path) diag.Diagnostics {
+		if err := check(v.(string)); err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       "Invalid firewall rule",
+				Detail:        err.Error(),
+				AttributePath: path,
+			}}
+		}
+		return nil
+	}
+}
+
+func validateFirewallAddressSyntax(address string) error {
+	return validateFirewallAddress(address, "")
+}
+
+func validateFirewallProtocol(protocol string) error {
+	if protocol != "" && !stringInSlice(protocol, firewallProtocols) {
+		return fmt.Errorf("%q is not one of %s", protocol, strings.Join(firewallProtocols, ", "))
+	}
+	return nil
+}
+
+func validateFirewallTCPFlags(tcpFlags string) error {
+	if tcpFlags != "" && !firewallTCPFlagsPattern.MatchString(tcpFlags) {
+		return fmt.Errorf("%q must be syn, fin, rst, psh, urg or ack, combined with | or &", tcpFlags)
+	}
+	return nil
+}
+
+// validateFirewallAddress checks that address is an IP or CIDR of the given ip_version.
+func validateFirewallAddress(address string, ipVersion string) error {
+	if address == "" {
+		return nil
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		var err error
+		if ip, _, err = net.ParseCIDR(address); err != nil {
+			return fmt.Errorf("%q is not a valid IP address or CIDR", address)
+		}
+	}
+
+	addressVersion := firewallIPVersion(ip)
+	if ipVersion != "" && ipVersion != addressVersion {
+		return fmt.Errorf("%q is an %s address but the rule has ip_version %q", address, addressVersion, ipVersion)
+	}
+	return nil
+}
+
+func validateFirewallPort(port string) error {
+	if port == "" {
+		return nil
+	}
+
+	m := firewallPortPattern.FindStringSubmatch(port)
+	if m == nil {
+		return fmt.Errorf("%q must be a port or a port range like 1024-65535", port)
+	}
+
+	start, _ := strconv.Atoi(m[1])
+	end := start
+	if m[2] != "" {
+		end, _ = strconv.Atoi(m[2])
+	}
+	if start > 65535 || end > 65535 {
+		return fmt.Errorf("%q is out of the port range 0-65535", port)
+	}
+	if start > end {
+		return fmt.Errorf("%q is an inverted port range", port)
+	}
+	return nil
+}
+
+// inferFirewallIPVersion returns the IP version shared by all given addresses,
+// or an empty string if there is none or they differ.
+func inferFirewallIPVersion(addresses ...string) string {
+	version := ""
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			ip, _, _ = net.ParseCIDR(address)
+		}
+		if ip == nil {
+			continue
+		}
+		if version != "" && version != firewallIPVersion(ip) {
+			return ""
+		}
+		version = firewallIPVersion(ip)
+	}
+	return version
+}
+
+func firewallIPVersion(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, item := range list {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateFirewallPort(t *testing.T) {
+	cases := []struct {
+		port    string
+		wantErr bool
+	}{
+		{"", false},
+		{"22", false},
+		{"0", false},
+		{"65535", false},
+		{"1024-65535", false},
+		{"80-80", false},
+		{"65536", true},
+		{"1-70000", true},
+		{"443-80", true},
+		{"http", true},
+		{"22,80", true},
+		{"-22", true},
+		{"22-", true},
+		{"123456", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.port, func(t *testing.T) {
+			if err := validateFirewallPort(tc.port); (err != nil) != tc.wantErr {
+				t.Errorf("validateFirewallPort(%q) error = %v, want error %v", tc.port, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFirewallAddress(t *testing.T) {
+	cases := []struct {
+		address   string
+		ipVersion string
+		wantErr   bool
+	}{
+		{"", "", false},
+		{"", "ipv6", false},
+		{"192.0.2.1", "", false},
+		{"192.0.2.0/24", "ipv4", false},
+		{"2001:db8::1", "ipv6", false},
+		{"2001:db8::/64", "", false},
+		{"192.0.2.1", "ipv6", true},
+		{"2001:db8::/64", "ipv4", true},
+		{"192.0.2.0/33", "", true},
+		{"192.0.2", "", true},
+		{"example.com", "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.address+"/"+tc.ipVersion, func(t *testing.T) {
+			if err := validateFirewallAddress(tc.address, tc.ipVersion); (err != nil) != tc.wantErr {
+				t.Errorf("validateFirewallAddress(%q, %q) error = %v, want error %v", tc.address, tc.ipVersion, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestInferFirewallIPVersion(t *testing.T) {
+	cases := []struct {
+		name      string
+		addresses []string
+		want      string
+	}{
+		{"none", nil, ""},
+		{"empty", []string{"", ""}, ""},
+		{"ipv4", []string{"192.0.2.1"}, "ipv4"},
+		{"ipv4 cidr and empty", []string{"192.0.2.0/24", ""}, "ipv4"},
+		{"ipv6", []string{"2001:db8::1", "2001:db8::/64"}, "ipv6"},
+		{"mixed", []string{"192.0.2.1", "2001:db8::1"}, ""},
+		{"invalid ignored", []string{"nonsense", "2001:db8::1"}, "ipv6"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := inferFirewallIPVersion(tc.addresses...); got != tc.want {
+				t.Errorf("inferFirewallIPVersion(%q) = %q, want %q", tc.addresses, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFirewallRuleFieldValidation(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"server_ip": "192.0.2.1",
+		"active":    true,
+		"rule": []interface{}{
+			map[string]interface{}{"action": "accept", "dst_port": "22"},
+			map[string]interface{}{"action": "accept", "src_ip": "192.0.2.0/33", "dst_port": "443-80", "protocol": "sctp", "tcp_flags": "syn+ack"},
+		},
+	})
+
+	diags := resourceFirewall().Validate(config)
+	want := map[string]bool{"src_ip": false, "dst_port": false, "protocol": false, "tcp_flags": false}
+	for _, d := range diags {
+		wantPath := func(field string) cty.Path {
+			return cty.GetAttrPath("rule").IndexInt(1).GetAttr(field)
+		}
+		for field := range want {
+			if d.AttributePath.Equals(wantPath(field)) {
+				want[field] = true
+			}
+		}
+	}
+	for field, found := range want {
+		if !found {
+			t.Errorf("no diagnostic at rule.1.%s, got %v", field, diags)
+		}
+	}
+	if len(diags) != len(want) {
+		t.Errorf("got %d diagnostics, want %d: %v", len(diags), len(want), diags)
+	}
+}
+
+func TestResourceFirewallCustomizeDiff(t *testing.T) {
+	accept := func(fields map[string]interface{}) interface{} {
+		rule := map[string]interface{}{"action": "accept"}
+		for k, v := range fields {
+			rule[k] = v
+		}
+		return rule
+	}
+	rules := func(n int) []interface{} {
+		var list []interface{}
+		for i := 0; i < n; i++ {
+			list = append(list, accept(map[string]interface{}{"dst_port": "22"}))
+		}
+		return list
+	}
+
+	cases := []struct {
+		name       string
+		rules      []interface{}
+		outputRule []interface{}
+		want       []string
+	}{
+		{"valid", []interface{}{
+			accept(map[string]interface{}{"src_ip": "192.0.2.0/24", "dst_ip": "192.0.2.1", "protocol": "tcp", "tcp_flags": "syn"}),
+			accept(map[string]interface{}{"ip_version": "ipv6", "src_ip": "2001:db8::/32"}),
+		}, nil, nil},
+		{"rule limit", rules(firewallMaxRules), rules(firewallMaxRules), nil},
+		{"too many rules", rules(firewallMaxRules + 1), rules(firewallMaxRules + 2), []string{"rule: ", "output_rule: "}},
+		{"mixed IP versions", []interface{}{
+			accept(map[string]interface{}{"src_ip": "192.0.2.1", "dst_ip": "2001:db8::1"}),
+		}, nil, []string{"rule.0: "}},
+		{"address of another ip_version", []interface{}{
+			accept(map[string]interface{}{"dst_port": "22"}),
+			accept(map[string]interface{}{"ip_version": "ipv4", "src_ip": "192.0.2.1", "dst_ip": "2001:db8::1"}),
+		}, nil, []string{"rule.1.dst_ip: "}},
+		{"tcp_flags without tcp", []interface{}{
+			accept(map[string]interface{}{"protocol": "udp", "tcp_flags": "syn"}),
+		}, []interface{}{
+			accept(map[string]interface{}{"protocol": "tcp", "tcp_flags": "syn"}),
+			accept(map[string]interface{}{"tcp_flags": "ack"}),
+		}, []string{"rule.0.tcp_flags: ", "output_rule.1.tcp_flags: "}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw := map[string]interface{}{"server_ip": "192.0.2.1", "active": true}
+			if tc.rules != nil {
+				raw["rule"] = tc.rules
+			}
+			if tc.outputRule != nil {
+				raw["output_rule"] = tc.outputRule
+			}
+			_, err := resourceFirewall().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+
+			var got []string
+			if err != nil {
+				got = strings.Split(err.Error(), "\n")
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got errors %q, want %d starting with %q", got, len(tc.want), tc.want)
+			}
+			for i, want := range tc.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("error %q does not start with %q", got[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package hetznerrobot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/ssh"
+)
+
+// installimageConfigKeys are the attributes rendered into the installimage
+// configuration, changing any of them installs the server again.
+var installimageConfigKeys = []string{"drives", "swraid", "swraid_level", "bootloader", "hostname", "partition", "image", "extra_config"}
+
+// installationAttributePaths maps rescue activation and reset request
+// parameters to resource attributes.
+var installationAttributePaths = attributePaths(map[string]string{
+	"authorized_key": "rescue_authorized_keys",
+	"type":           "reset_type",
+})
+
+// resourceInstallation installs an operating system with installimage from
+// the rescue system. The host key of the installed system is checked against
+// expected_host_key, without it the first key other than the rescue system's
+// is trusted. Destroying it only removes it from state, the installed system
+// is left as is.
+func resourceInstallation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceInstallationCreate,
+		ReadContext:   resourceInstallationRead,
+		UpdateContext: resourceInstallationUpdate,
+		DeleteContext: resourceInstallationDelete,
+		CustomizeDiff: resourceInstallationCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Server ID",
+			},
+			"drives": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Drives to install to, e.g. /dev/nvme0n1",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"swraid": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Set up a software RAID over the drives",
+			},
+			"swraid_level": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          1,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntInSlice([]int{0, 1, 5, 6, 10})),
+				Description:      "Software RAID level, one of 0, 1, 5, 6 or 10",
+			},
+			"bootloader": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Bootloader, installimage picks one if not set",
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Hostname of the installed system",
+			},
+			"partition": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Partitions, in the order they are created",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"mount_point": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Mount point, or swap",
+						},
+						"filesystem": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Filesystem, e.g. ext4, xfs or swap",
+						},
+						"size": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Size, e.g. 512M, 32G or all for the remaining space",
+						},
+					},
+				},
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Path or URL of the image to install, e.g. /root/.oldroot/nfs/images/Ubuntu-2404-noble-amd64-base.tar.gz",
+			},
+			"extra_config": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Additional installimage configuration appended as is, e.g. LV lines or CRYPTPASSWORD",
+			},
+			"rescue_authorized_keys": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Fingerprints of the SSH keys authorized in the rescue system",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"ssh_private_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateSSHPrivateKey),
+				Description:      "Private key to log into the rescue system with, the rescue password is used if not set",
+			},
+			"ssh_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Host to connect to, defaults to the server IP",
+			},
+			"ssh_port": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          22,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+				Description:      "SSH port of the rescue and the installed system",
+			},
+			"reset_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "hw",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(serverResetTypes, false)),
+				Description:      "Reset type used to boot into the rescue system, one of " + strings.Join(serverResetTypes, ", "),
+			},
+			"expected_host_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateSSHPublicKey),
+				Description: "SSH host key the installed system must present, in authorized_keys format, e.g. one " +
+					"installed by extra_config. Without it the first host key other than the rescue system's is trusted on first use",
+			},
+			"installimage_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "/root/.oldroot/nfs/install/installimage",
+				Description: "Path of installimage in the rescue system",
+			},
+			// read-only / computed
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server IP",
+			},
+			"config": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Rendered installimage configuration, sensitive as extra_config may hold secrets",
+			},
+			"config_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA256 of the rendered configuration, the server is installed again when it changes",
+			},
+			"host_public_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SSH host key of the installed system in authorized_keys format",
+			},
+			"host_key_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA256 fingerprint of the SSH host key of the installed system",
+			},
+		},
+	}
+}
+
+// resourceInstallationCustomizeDiff renders the installimage configuration at
+// plan time and replaces the installation when its hash changes.
+func resourceInstallationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range installimageConfigKeys {
+		if d.NewValueKnown(key) {
+			continue
+		}
+		d.SetNewComputed("config")
+		d.SetNewComputed("config_hash")
+		if d.Id() != "" {
+			return d.ForceNew(key)
+		}
+		return nil
+	}
+
+	config := renderInstallimageConfig(d.Get)
+	hash := installimageConfigHash(config)
+	if old, _ := d.GetChange("config_hash"); old.(string) == hash {
+		return nil
+	}
+
+	d.SetNew("config", config)
+	d.SetNew("config_hash", hash)
+	if d.Id() != "" {
+		return d.ForceNew("config_hash")
+	}
+
+	if !d.NewValueKnown("server_id") {
+		return nil
+	}
+	c := meta.(*HetznerRobotClient)
+	serverID := d.Get("server_id").(int)
+	server, err := c.getServer(ctx, serverID)
+	if err != nil {
+		return fmt.Errorf("unable to find server %d: %w", serverID, err)
+	}
+	if !server.Rescue {
+		return fmt.Errorf("server_id: server %d does not support the rescue system", serverID)
+	}
+	return nil
+}
+
+func resourceInstallationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
+	serverID := d.Get("server_id").(int)
+	server, err := c.getServer(ctx, serverID)
+	if err != nil {
+		return diag.Errorf("Unable to find server %d:\n\t %q", serverID, err)
+	}
+
+	host := d.Get("ssh_host").(string)
+	if host == "" {
+		host = server.ServerIP
+	}
+	port := d.Get("ssh_port").(int)
+	config := renderInstallimageConfig(d.Get)
+
+	// the password is only handed out when the rescue system is activated
+	if boot, err := c.getBoot(ctx, serverID); err == nil && boot.ActiveProfile == bootProfileRescue {
+		if err := c.deleteBootProfile(ctx, serverID, bootProfileRescue); err != nil {
+			return diag.Errorf("Unable to deactivate the rescue system of server %d:\n\t %q", serverID, err)
+		}
+	}
+	rescue, err := c.setBootProfile(ctx, serverID, bootProfileRescue, "", "linux", "", "", expandStringList(d.Get("rescue_authorized_keys").([]interface{})))
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to activate the rescue system of server %d", serverID), installationAttributePaths)
+	}
+
+	auth, err := rescueAuthMethods(d.Get("ssh_private_key").(string), rescue.Password)
+	if err != nil {
+		return diag.Errorf("Unable to use ssh_private_key:\n\t %q", err)
+	}
+
+	var expectedHostKey ssh.PublicKey
+	if v := d.Get("expected_host_key").(string); v != "" {
+		if expectedHostKey, _, _, _, err = ssh.ParseAuthorizedKey([]byte(v)); err != nil {
+			return diag.Errorf("Unable to use expected_host_key:\n\t %q", err)
+		}
+	}
+
+	resetType := d.Get("reset_type").(string)
+	if _, err := c.resetServer(ctx, serverID, resetType); err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to reset server %d", serverID), installationAttributePaths)
+	}
+	// the server is wiped from here on, keep it in state so a failure taints it
+	d.SetId(strconv.Itoa(serverID))
+	d.Set("config", config)
+	d.Set("config_hash", installimageConfigHash(config))
+	tflog.Info(ctx, "server reset into the rescue system", map[string]interface{}{
+		"server_id": serverID,
+		"type":      resetType,
+	})
+
+	if diags := waitForServerPort(ctx, host, port, time.Until(deadline)); diags.HasError() {
+		return diags
+	}
+
+	installimagePath := d.Get("installimage_path").(string)
+	client, rescueHostKey, err := waitForRescueSystem(ctx, host, port, auth, rescue.HostKeys, installimagePath, time.Until(deadline))
+	if err != nil {
+		return diag.Errorf("Unable to log into the rescue system of server %d:\n\t %q", serverID, err)
+	}
+	defer client.Close()
+
+	if err := runSSHCommand(ctx, client, "cat > "+installimageConfigPath, strings.NewReader(config)); err != nil {
+		return diag.Errorf("Unable to write the installimage configuration to server %d:\n\t %q", serverID, err)
+	}
+	if err := runSSHCommand(ctx, client, installimagePath+" -a -c "+installimageConfigPath, nil); err != nil {
+		return diag.Errorf("Unable to install server %d:\n\t %q", serverID, err)
+	}
+	tflog.Info(ctx, "installimage finished, rebooting", map[string]interface{}{
+		"server_id": serverID,
+	})
+	rebootServer(ctx, client)
+
+	hostKey, err := waitForInstalledSystem(ctx, host, port, rescueHostKey, expectedHostKey, time.Until(deadline))
+	if err != nil {
+		return diag.Errorf("Unable to verify the host key of the installed system on server %d:\n\t %q", serverID, err)
+	}
+
+	d.Set("host_public_key", strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostKey))))
+	d.Set("host_key_fingerprint", ssh.FingerprintSHA256(hostKey))
+
+	return resourceInstallationRead(ctx, d, meta)
+}
+
+func resourceInstallationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	server, err := c.getServer(ctx, serverID)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "server not found, removing installation from state", map[string]interface{}{
+				"server_id": serverID,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find server %d:\n\t %q", serverID, err)
+	}
+
+	d.Set("server_ip", server.ServerIP)
+
+	return nil
+}
+
+// resourceInstallationUpdate only handles the connection settings, changes to
+// the installimage configuration replace the installation.
+func resourceInstallationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceInstallationRead(ctx, d, meta)
+}
+
+func resourceInstallationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// renderInstallimageConfig renders the installimage configuration from the
+// attributes returned by get.
+func renderInstallimageConfig(get func(string) interface{}) string {
+	var b strings.Builder
+	for i, drive := range get("drives").([]interface{}) {
+		fmt.Fprintf(&b, "DRIVE%d %s\n", i+1, drive)
+	}
+	if get("swraid").(bool) {
+		b.WriteString("SWRAID 1\n")
+		fmt.Fprintf(&b, "SWRAIDLEVEL %d\n", get("swraid_level").(int))
+	} else {
+		b.WriteString("SWRAID 0\n")
+	}
+	if bootloader := get("bootloader").(string); bootloader != "" {
+		fmt.Fprintf(&b, "BOOTLOADER %s\n", bootloader)
+	}
+	fmt.Fprintf(&b, "HOSTNAME %s\n", get("hostname"))
+	for _, p := range get("partition").([]interface{}) {
+		partition := p.(map[string]interface{})
+		fmt.Fprintf(&b, "PART %s %s %s\n", partition["mount_point"], partition["filesystem"], partition["size"])
+	}
+	fmt.Fprintf(&b, "IMAGE %s\n", get("image"))
+	if extra := strings.TrimSpace(get("extra_config").(string)); extra != "" {
+		b.WriteString(extra + "\n")
+	}
+	return b.String()
+}
+
+func installimageConfigHash(config string) string {
+	sum := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(sum[:])
+}
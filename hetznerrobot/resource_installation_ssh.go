@@ -0,0 +1,258 @@
+package hetznerrobot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"golang.org/x/crypto/ssh"
+)
+
+// installimageConfigPath is where the rendered configuration is written in
+// the rescue system.
+const installimageConfigPath = "/autosetup"
+
+// sshHandshakeTimeout bounds a single connection attempt, the servers are
+// polled until the create timeout expires.
+const sshHandshakeTimeout = 30 * time.Second
+
+// errHostKeyCaptured aborts a handshake once the host key is known.
+var errHostKeyCaptured = errors.New("host key captured")
+
+func validateSSHPrivateKey(v interface{}, path string) ([]string, []error) {
+	if _, err := ssh.ParsePrivateKey([]byte(v.(string))); err != nil {
+		return nil, []error{fmt.Errorf("%s: unable to parse private key: %w", path, err)}
+	}
+	return nil, nil
+}
+
+func validateSSHPublicKey(v interface{}, path string) ([]string, []error) {
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.(string))); err != nil {
+		return nil, []error{fmt.Errorf("%s: unable to parse public key: %w", path, err)}
+	}
+	return nil, nil
+}
+
+// rescueAuthMethods logs in with privateKey if given and falls back to the
+// rescue password.
+func rescueAuthMethods(privateKey string, password string) ([]ssh.AuthMethod, error) {
+	var auth []ssh.AuthMethod
+	if privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if password != "" {
+		auth = append(auth, ssh.Password(password))
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("Robot returned no rescue password, set ssh_private_key to log in with a key")
+	}
+	return auth, nil
+}
+
+// dialSSH opens an SSH connection to address, giving up on the handshake
+// after sshHandshakeTimeout.
+func dialSSH(ctx context.Context, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	dialer := net.Dialer{Timeout: sshHandshakeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(sshHandshakeTimeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// waitForRescueSystem logs into the rescue system as root and returns the
+// connection together with its host key. The host key is checked against the
+// fingerprints Robot reported for the rescue system, a server still running
+// the previous system is retried until timeout.
+func waitForRescueSystem(ctx context.Context, host string, port int, auth []ssh.AuthMethod, hostKeys []BootKey, installimagePath string, timeout time.Duration) (*ssh.Client, ssh.PublicKey, error) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	var client *ssh.Client
+	var hostKey ssh.PublicKey
+	err := retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		config := &ssh.ClientConfig{
+			User: "root",
+			Auth: auth,
+			HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+				if len(hostKeys) > 0 && !bootKeysContain(hostKeys, key) {
+					return fmt.Errorf("host key %s is not a host key of the rescue system", ssh.FingerprintLegacyMD5(key))
+				}
+				hostKey = key
+				return nil
+			},
+		}
+		conn, err := dialSSH(ctx, address, config)
+		if err != nil {
+			tflog.Debug(ctx, "rescue system not reachable yet", map[string]interface{}{
+				"address": address,
+				"error":   err.Error(),
+			})
+			return retry.RetryableError(err)
+		}
+		if err := runSSHCommand(ctx, conn, "test -x "+installimagePath, nil); err != nil {
+			conn.Close()
+			return retry.RetryableError(fmt.Errorf("%s is not running the rescue system: %w", address, err))
+		}
+		client = conn
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, hostKey, nil
+}
+
+// waitForInstalledSystem waits until the SSH server on host presents a host
+// key other than rescueHostKey and returns it. If expectedHostKey is set the
+// key has to match it, otherwise it is trusted on first use. Only the
+// handshake is done, the installed system does not have to accept any
+// credentials.
+func waitForInstalledSystem(ctx context.Context, host string, port int, rescueHostKey ssh.PublicKey, expectedHostKey ssh.PublicKey, timeout time.Duration) (ssh.PublicKey, error) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	var hostKey ssh.PublicKey
+	err := retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		var key ssh.PublicKey
+		config := &ssh.ClientConfig{
+			User: "root",
+			HostKeyCallback: func(hostname string, remote net.Addr, remoteKey ssh.PublicKey) error {
+				key = remoteKey
+				return errHostKeyCaptured
+			},
+		}
+		if expectedHostKey != nil {
+			config.HostKeyAlgorithms = hostKeyAlgorithms(expectedHostKey)
+		}
+		conn, err := dialSSH(ctx, address, config)
+		if conn != nil {
+			conn.Close()
+		}
+		if key == nil {
+			if err == nil {
+				err = errors.New("no host key presented")
+			}
+			return retry.RetryableError(err)
+		}
+		if bytes.Equal(key.Marshal(), rescueHostKey.Marshal()) {
+			return retry.RetryableError(fmt.Errorf("%s is still running the rescue system", address))
+		}
+		if expectedHostKey != nil && !bytes.Equal(key.Marshal(), expectedHostKey.Marshal()) {
+			return retry.NonRetryableError(fmt.Errorf("%s presented host key %s, expected %s",
+				address, ssh.FingerprintSHA256(key), ssh.FingerprintSHA256(expectedHostKey)))
+		}
+		hostKey = key
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tflog.Info(ctx, "installed system is up", map[string]interface{}{
+		"address":     address,
+		"fingerprint": ssh.FingerprintSHA256(hostKey),
+	})
+	return hostKey, nil
+}
+
+// hostKeyAlgorithms returns the algorithms to negotiate so that a server
+// holding several host keys presents key.
+func hostKeyAlgorithms(key ssh.PublicKey) []string {
+	if key.Type() == ssh.KeyAlgoRSA {
+		return []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return []string{key.Type()}
+}
+
+// bootKeysContain checks key against the MD5 fingerprints Robot reports for
+// boot configuration keys.
+func bootKeysContain(keys []BootKey, key ssh.PublicKey) bool {
+	fingerprint := ssh.FingerprintLegacyMD5(key)
+	for _, k := range keys {
+		if strings.EqualFold(k.Fingerprint, fingerprint) {
+			return true
+		}
+	}
+	return false
+}
+
+// runSSHCommand runs command on client and logs its output line by line.
+func runSSHCommand(ctx context.Context, client *ssh.Client, command string, stdin io.Reader) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	session.Stdin = stdin
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := session.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := session.Start(command); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go logSSHOutput(ctx, &wg, command, "stdout", stdout)
+	go logSSHOutput(ctx, &wg, command, "stderr", stderr)
+
+	done := make(chan error, 1)
+	go func() {
+		wg.Wait()
+		done <- session.Wait()
+	}()
+
+	select {
+	case <-ctx.Done():
+		session.Close()
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+func logSSHOutput(ctx context.Context, wg *sync.WaitGroup, command string, stream string, r io.Reader) {
+	defer wg.Done()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		tflog.Info(ctx, scanner.Text(), map[string]interface{}{
+			"command": command,
+			"stream":  stream,
+		})
+	}
+}
+
+// rebootServer reboots the server client is connected to. The connection
+// usually drops before the command returns, so errors are only logged.
+func rebootServer(ctx context.Context, client *ssh.Client) {
+	if err := runSSHCommand(ctx, client, "reboot", nil); err != nil {
+		tflog.Debug(ctx, "reboot command did not return cleanly", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package hetznerrobot
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+const testRescuePassword = "rescue-password"
+
+// sshStandIn plays the server: the rescue system until it is rebooted, the
+// installed system afterwards.
+type sshStandIn struct {
+	t            *testing.T
+	rescueKey    ssh.Signer
+	installedKey ssh.Signer
+	listener     net.Listener
+	rebooted     atomic.Bool
+
+	mu        sync.Mutex
+	commands  []string
+	autosetup string
+}
+
+func newSSHStandIn(t *testing.T) *sshStandIn {
+	return &sshStandIn{t: t, rescueKey: newTestSigner(t), installedKey: newTestSigner(t)}
+}
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// listen starts accepting connections on address, like a server coming up.
+func (s *sshStandIn) listen(address string) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		s.t.Errorf("unable to listen on %s: %v", address, err)
+		return
+	}
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *sshStandIn) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		s.listener.Close()
+	}
+}
+
+func (s *sshStandIn) serve(conn net.Conn) {
+	installed := s.rebooted.Load()
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(meta ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if !installed && meta.User() == "root" && string(password) == testRescuePassword {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("access denied for %s", meta.User())
+		},
+	}
+	if installed {
+		config.AddHostKey(s.installedKey)
+	} else {
+		config.AddHostKey(s.rescueKey)
+	}
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.exec(channel, requests)
+	}
+}
+
+func (s *sshStandIn) exec(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+	for req := range requests {
+		if req.Type != "exec" {
+			req.Reply(false, nil)
+			continue
+		}
+		command := string(req.Payload[4:])
+		req.Reply(true, nil)
+
+		s.mu.Lock()
+		s.commands = append(s.commands, command)
+		s.mu.Unlock()
+
+		switch {
+		case strings.HasPrefix(command, "cat > "+installimageConfigPath):
+			config, _ := io.ReadAll(channel)
+			s.mu.Lock()
+			s.autosetup = string(config)
+			s.mu.Unlock()
+		case strings.Contains(command, "installimage -a -c "+installimageConfigPath):
+			io.WriteString(channel, "Creating partitions and /etc/fstab\nExtracting image\n")
+			io.WriteString(channel.Stderr(), "WARNING: test drive has no SMART data\n")
+		case command == "reboot":
+			s.rebooted.Store(true)
+			s.close()
+			go s.listen(s.address())
+			return
+		}
+
+		status := make([]byte, 4)
+		binary.BigEndian.PutUint32(status, 0)
+		channel.SendRequest("exit-status", false, status)
+		return
+	}
+}
+
+func (s *sshStandIn) address() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listener.Addr().String()
+}
+
+func (s *sshStandIn) recorded() ([]string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...), s.autosetup
+}
+
+// newRobotStandIn serves the Robot endpoints used by an installation. The
+// SSH stand-in comes up on address once the server is reset.
+func newRobotStandIn(t *testing.T, standIn *sshStandIn, address string, rescueHostKey ssh.PublicKey) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/server/1":
+			fmt.Fprint(w, `{"server":{"server_ip":"127.0.0.1","server_number":1,"rescue":true,"reset":true}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/boot/1":
+			fmt.Fprint(w, `{"boot":{"rescue":{"server_number":1,"active":false}}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/boot/1/rescue":
+			fmt.Fprintf(w, `{"rescue":{"server_ip":"127.0.0.1","server_number":1,"os":"linux","active":true,"password":%q,"authorized_key":[],"host_key":[{"key":{"fingerprint":%q,"type":"ED25519","size":256}}]}}`,
+				testRescuePassword, ssh.FingerprintLegacyMD5(rescueHostKey))
+		case r.Method == http.MethodPost && r.URL.Path == "/reset/1":
+			go func() {
+				// give the port check a chance to see the server go down
+				time.Sleep(200 * time.Millisecond)
+				standIn.listen(address)
+			}()
+			fmt.Fprint(w, `{"reset":{"server_ip":"127.0.0.1","type":"hw"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func freeLocalAddress(t *testing.T) (string, int) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String(), listener.Addr().(*net.TCPAddr).Port
+}
+
+func testInstallationData(t *testing.T, port int, extra map[string]interface{}) *schema.ResourceData {
+	raw := map[string]interface{}{
+		"server_id": 1,
+		"drives":    []interface{}{"/dev/nvme0n1", "/dev/nvme1n1"},
+		"swraid":    true,
+		"hostname":  "node1.example.com",
+		"image":     "/root/.oldroot/nfs/images/Ubuntu-2404-noble-amd64-base.tar.gz",
+		"partition": []interface{}{
+			map[string]interface{}{"mount_point": "/boot", "filesystem": "ext3", "size": "1G"},
+			map[string]interface{}{"mount_point": "/", "filesystem": "ext4", "size": "all"},
+		},
+		"ssh_host": "127.0.0.1",
+		"ssh_port": port,
+	}
+	for k, v := range extra {
+		raw[k] = v
+	}
+	return schema.TestResourceDataRaw(t, resourceInstallation().Schema, raw)
+}
+
+func TestResourceInstallationCreate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected bool
+	}{
+		{"trust on first use", false},
+		{"expected host key", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			standIn := newSSHStandIn(t)
+			t.Cleanup(standIn.close)
+			address, port := freeLocalAddress(t)
+			robot := newRobotStandIn(t, standIn, address, standIn.rescueKey.PublicKey())
+
+			extra := map[string]interface{}{}
+			if tc.expected {
+				extra["expected_host_key"] = string(ssh.MarshalAuthorizedKey(standIn.installedKey.PublicKey()))
+			}
+			d := testInstallationData(t, port, extra)
+
+			var logs bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &logs)
+			c := NewHetznerRobotClient("user", "password", robot.URL, 0, time.Second)
+
+			if diags := resourceInstallationCreate(ctx, d, c); diags.HasError() {
+				t.Fatalf("create failed: %v", diags)
+			}
+
+			commands, autosetup := standIn.recorded()
+			wantCommands := []string{
+				"test -x /root/.oldroot/nfs/install/installimage",
+				"cat > /autosetup",
+				"/root/.oldroot/nfs/install/installimage -a -c /autosetup",
+				"reboot",
+			}
+			if strings.Join(commands, "\n") != strings.Join(wantCommands, "\n") {
+				t.Errorf("commands run in the rescue system:\n%q\nwant\n%q", commands, wantCommands)
+			}
+			if autosetup != d.Get("config").(string) {
+				t.Errorf("uploaded /autosetup:\n%s\nwant\n%s", autosetup, d.Get("config"))
+			}
+			if d.Get("config_hash").(string) != installimageConfigHash(autosetup) {
+				t.Errorf("config_hash %s does not match the uploaded configuration", d.Get("config_hash"))
+			}
+
+			installedKey := standIn.installedKey.PublicKey()
+			if got, want := d.Get("host_key_fingerprint").(string), ssh.FingerprintSHA256(installedKey); got != want {
+				t.Errorf("host_key_fingerprint = %s, want %s of the installed system", got, want)
+			}
+			if got, want := d.Get("host_public_key").(string), strings.TrimSpace(string(ssh.MarshalAuthorizedKey(installedKey))); got != want {
+				t.Errorf("host_public_key = %s, want %s", got, want)
+			}
+			if d.Id() != "1" || d.Get("server_ip").(string) != "127.0.0.1" {
+				t.Errorf("id %q, server_ip %q", d.Id(), d.Get("server_ip"))
+			}
+
+			for _, line := range []string{`"@message":"Extracting image"`, `"stream":"stdout"`, `"@message":"WARNING: test drive has no SMART data"`, `"stream":"stderr"`} {
+				if !strings.Contains(logs.String(), line) {
+					t.Errorf("installimage output %s not logged", line)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceInstallationCreateRejectsRescueHostKey(t *testing.T) {
+	standIn := newSSHStandIn(t)
+	t.Cleanup(standIn.close)
+	address, port := freeLocalAddress(t)
+	// Robot reports a different rescue host key than the server presents
+	robot := newRobotStandIn(t, standIn, address, newTestSigner(t).PublicKey())
+
+	d := testInstallationData(t, port, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := NewHetznerRobotClient("user", "password", robot.URL, 0, time.Second)
+
+	diags := resourceInstallationCreate(ctx, d, c)
+	if !diags.HasError() {
+		t.Fatal("create succeeded against a server with an unknown rescue host key")
+	}
+	if commands, _ := standIn.recorded(); len(commands) > 0 {
+		t.Errorf("ran %q on a server with an unknown host key", commands)
+	}
+	// the server was reset, the failed installation stays in state to be tainted
+	if d.Id() != "1" {
+		t.Errorf("id %q, want the installation kept in state after the reset", d.Id())
+	}
+}
+
+func TestResourceInstallationCreateRejectsUnexpectedHostKey(t *testing.T) {
+	standIn := newSSHStandIn(t)
+	t.Cleanup(standIn.close)
+	address, port := freeLocalAddress(t)
+	robot := newRobotStandIn(t, standIn, address, standIn.rescueKey.PublicKey())
+
+	d := testInstallationData(t, port, map[string]interface{}{
+		"expected_host_key": string(ssh.MarshalAuthorizedKey(newTestSigner(t).PublicKey())),
+	})
+	c := NewHetznerRobotClient("user", "password", robot.URL, 0, time.Second)
+
+	diags := resourceInstallationCreate(context.Background(), d, c)
+	if !diags.HasError() {
+		t.Fatal("create accepted a host key other than expected_host_key")
+	}
+	if !strings.Contains(diags[0].Summary, "host key") {
+		t.Errorf("unexpected error %v", diags)
+	}
+	if commands, _ := standIn.recorded(); len(commands) == 0 || commands[len(commands)-1] != "reboot" {
+		t.Errorf("commands %q, want the installation to have run", commands)
+	}
+	if d.Id() != "1" || d.Get("host_key_fingerprint").(string) != "" {
+		t.Errorf("id %q, host_key_fingerprint %q", d.Id(), d.Get("host_key_fingerprint"))
+	}
+}
+
+func TestRenderInstallimageConfig(t *testing.T) {
+	d := testInstallationData(t, 22, map[string]interface{}{
+		"swraid_level": 0,
+		"bootloader":   "grub",
+		"extra_config": "\nLV vg0 root / ext4 50G\n",
+	})
+
+	want := strings.Join([]string{
+		"DRIVE1 /dev/nvme0n1",
+		"DRIVE2 /dev/nvme1n1",
+		"SWRAID 1",
+		"SWRAIDLEVEL 0",
+		"BOOTLOADER grub",
+		"HOSTNAME node1.example.com",
+		"PART /boot ext3 1G",
+		"PART / ext4 all",
+		"IMAGE /root/.oldroot/nfs/images/Ubuntu-2404-noble-amd64-base.tar.gz",
+		"LV vg0 root / ext4 50G",
+	}, "\n") + "\n"
+	if got := renderInstallimageConfig(d.Get); got != want {
+		t.Errorf("rendered\n%s\nwant\n%s", got, want)
+	}
+
+	d = testInstallationData(t, 22, map[string]interface{}{"swraid": false})
+	if got := renderInstallimageConfig(d.Get); !strings.Contains(got, "SWRAID 0\n") || strings.Contains(got, "SWRAIDLEVEL") {
+		t.Errorf("rendered without software RAID\n%s", got)
+	}
+}
+
+func TestResourceInstallationConfigIsSensitive(t *testing.T) {
+	s := resourceInstallation().Schema
+	for _, key := range []string{"extra_config", "config"} {
+		if !s[key].Sensitive {
+			t.Errorf("%s is not sensitive, it may hold installimage secrets", key)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceIP manages the settings of an IP address assigned to a server.
+// separate_mac is only acted on when it is set in the configuration, and only
+// a separate MAC the resource generated, tracked in mac_generated, is ever
+// removed. Destroying it leaves a MAC that existed before and the traffic
+// warning settings as they are.
+func resourceIP() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceIPCreate,
+		ReadContext:   resourceIPRead,
+		UpdateContext: resourceIPUpdate,
+		DeleteContext: resourceIPDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: withTrafficWarningsSchema(map[string]*schema.Schema{
+			"ip": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				Description:      "IP address",
+			},
+			"separate_mac": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Generate a separate MAC address for the IP, only possible for additional single IPs. Left as is if not set",
+			},
+			// read-only / computed
+			"mac": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Separate MAC address, empty if there is none",
+			},
+			"mac_generated": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the separate MAC was generated by this resource and is removed on destroy",
+			},
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Main IP of the server the IP is assigned to",
+			},
+			"server_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of the server the IP is assigned to",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the IP is locked",
+			},
+		}),
+	}
+}
+
+func resourceIPCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Get("ip").(string)
+	current, err := c.getIP(ctx, ip)
+	if err != nil {
+		return diag.Errorf("Unable to find IP %s:\n\t %q", ip, err)
+	}
+
+	separateMac, configured := ipSeparateMacConfig(d)
+	if configured && !separateMac && current.SeparateMac != "" {
+		return diag.Errorf("Unable to manage IP %s:\n\t %q", ip, errForeignIPMac)
+	}
+
+	d.SetId(current.IP)
+
+	if diags := updateIPTrafficWarnings(ctx, c, d, current); diags.HasError() {
+		return diags
+	}
+	if separateMac && current.SeparateMac == "" {
+		if _, err := c.createIPMac(ctx, ip); err != nil {
+			return diag.Errorf("Unable to generate separate MAC for IP %s:\n\t %q", ip, err)
+		}
+		d.Set("mac_generated", true)
+	}
+
+	return resourceIPRead(ctx, d, meta)
+}
+
+func resourceIPRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ipAddress := d.Id()
+	ip, err := c.getIP(ctx, ipAddress)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "IP not found, removing from state", map[string]interface{}{
+				"ip": ipAddress,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find IP %s:\n\t %q", ipAddress, err)
+	}
+
+	d.Set("ip", ip.IP)
+	flattenTrafficWarnings(d, ip.HetznerRobotTrafficWarnings)
+	d.Set("separate_mac", ip.SeparateMac != "")
+	d.Set("mac", ip.SeparateMac)
+	if ip.SeparateMac == "" {
+		d.Set("mac_generated", false)
+	}
+	d.Set("server_ip", ip.ServerIP)
+	d.Set("server_number", ip.ServerNumber)
+	d.Set("locked", ip.Locked)
+
+	return nil
+}
+
+func resourceIPUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	if d.HasChanges(trafficWarningsKeys...) {
+		current, err := c.getIP(ctx, ip)
+		if err != nil {
+			return diag.Errorf("Unable to find IP %s:\n\t %q", ip, err)
+		}
+		if diags := updateIPTrafficWarnings(ctx, c, d, current); diags.HasError() {
+			return diags
+		}
+	}
+
+	if separateMac, configured := ipSeparateMacConfig(d); configured && d.HasChange("separate_mac") {
+		if separateMac {
+			if _, err := c.createIPMac(ctx, ip); err != nil {
+				return diag.Errorf("Unable to generate separate MAC for IP %s:\n\t %q", ip, err)
+			}
+			d.Set("mac_generated", true)
+		} else {
+			if !d.Get("mac_generated").(bool) {
+				return diag.Errorf("Unable to remove separate MAC of IP %s:\n\t %q", ip, errForeignIPMac)
+			}
+			if err := c.deleteIPMac(ctx, ip); err != nil && !IsNotFound(err) {
+				return diag.Errorf("Unable to remove separate MAC of IP %s:\n\t %q", ip, err)
+			}
+			d.Set("mac_generated", false)
+		}
+	}
+
+	return resourceIPRead(ctx, d, meta)
+}
+
+func resourceIPDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	// a separate MAC that existed before the IP was managed is left alone
+	if d.Get("mac_generated").(bool) {
+		if err := c.deleteIPMac(ctx, ip); err != nil && !IsNotFound(err) {
+			return diag.Errorf("Unable to remove separate MAC of IP %s:\n\t %q", ip, err)
+		}
+	}
+
+	return nil
+}
+
+// errForeignIPMac refuses to remove a separate MAC the resource did not
+// generate, it may be in use by a virtual machine.
+var errForeignIPMac = errors.New("the separate MAC was not generated by this resource, remove it in Robot or do not set separate_mac to false")
+
+// ipSeparateMacConfig returns separate_mac and whether it is set in the
+// configuration, an unset value follows whatever Robot reports.
+func ipSeparateMacConfig(d *schema.ResourceData) (bool, bool) {
+	v := d.GetRawConfig().GetAttr("separate_mac")
+	if v.IsNull() || !v.IsKnown() {
+		return false, false
+	}
+	return v.True(), true
+}
+
+// updateIPTrafficWarnings sends the configured traffic warning settings if
+// they differ from the current ones.
+func updateIPTrafficWarnings(ctx context.Context, c *HetznerRobotClient, d *schema.ResourceData, current *HetznerRobotIP) diag.Diagnostics {
+	warnings := expandTrafficWarnings(d, current.HetznerRobotTrafficWarnings)
+	if warnings == current.HetznerRobotTrafficWarnings {
+		return nil
+	}
+
+	if _, err := c.setIPTrafficWarnings(ctx, current.IP, warnings); err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to update traffic warnings of IP %s", current.IP), trafficWarningsAttributePaths)
+	}
+	return nil
+}
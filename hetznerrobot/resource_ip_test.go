@@ -0,0 +1,188 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testIP = "192.0.2.10"
+
+// ipStandIn serves the Robot IP endpoints for testIP and records the MAC
+// changes made through them.
+type ipStandIn struct {
+	mu       sync.Mutex
+	mac      string
+	requests []string
+}
+
+func newIPStandIn(t *testing.T, mac string) (*ipStandIn, *HetznerRobotClient) {
+	s := &ipStandIn{mac: mac}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/ip/"+testIP:
+			separateMac := "null"
+			if s.mac != "" {
+				separateMac = fmt.Sprintf("%q", s.mac)
+			}
+			fmt.Fprintf(w, `{"ip":{"ip":%q,"server_ip":"192.0.2.1","server_number":1,"locked":false,"separate_mac":%s,"traffic_warnings":false,"traffic_hourly":200,"traffic_daily":2000,"traffic_monthly":20}}`, testIP, separateMac)
+		case r.Method == http.MethodPut && r.URL.Path == "/ip/"+testIP+"/mac":
+			s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+			s.mac = "00:50:56:00:00:02"
+			fmt.Fprintf(w, `{"mac":{"ip":%q,"mac":%q}}`, testIP, s.mac)
+		case r.Method == http.MethodDelete && r.URL.Path == "/ip/"+testIP+"/mac":
+			s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+			s.mac = ""
+			fmt.Fprintf(w, `{"mac":{"ip":%q,"mac":null}}`, testIP)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return s, NewHetznerRobotClient("user", "password", server.URL, 0, time.Second)
+}
+
+func (s *ipStandIn) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// testRawConfig returns the configuration Terraform would send for r with
+// values set and everything else null.
+func testRawConfig(r *schema.Resource, values map[string]cty.Value) cty.Value {
+	attrs := map[string]cty.Value{}
+	for name, ty := range r.CoreConfigSchema().ImpliedType().AttributeTypes() {
+		attrs[name] = cty.NullVal(ty)
+	}
+	for name, value := range values {
+		attrs[name] = value
+	}
+	return cty.ObjectVal(attrs)
+}
+
+// applyIP plans and applies config against state like Terraform does.
+func applyIP(t *testing.T, c *HetznerRobotClient, state map[string]string, config map[string]interface{}) (*terraform.InstanceDiff, *terraform.InstanceState, error) {
+	t.Helper()
+	r := resourceIP()
+	values := map[string]cty.Value{}
+	for k, v := range config {
+		switch v := v.(type) {
+		case string:
+			values[k] = cty.StringVal(v)
+		case bool:
+			values[k] = cty.BoolVal(v)
+		}
+	}
+	s := &terraform.InstanceState{ID: state["id"], Attributes: state, RawConfig: testRawConfig(r, values)}
+	ctx := context.Background()
+	diff, err := r.Diff(ctx, s, terraform.NewResourceConfigRaw(config), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil {
+		return nil, s, nil
+	}
+	newState, diags := r.Apply(ctx, s, diff, c)
+	if diags.HasError() {
+		return diff, newState, fmt.Errorf("%v", diags)
+	}
+	return diff, newState, nil
+}
+
+func testIPState(separateMac bool, macGenerated bool) map[string]string {
+	return map[string]string{
+		"id":               testIP,
+		"ip":               testIP,
+		"separate_mac":     fmt.Sprint(separateMac),
+		"mac":              "00:50:56:00:00:01",
+		"mac_generated":    fmt.Sprint(macGenerated),
+		"server_ip":        "192.0.2.1",
+		"server_number":    "1",
+		"locked":           "false",
+		"traffic_warnings": "false",
+		"traffic_hourly":   "200",
+		"traffic_daily":    "2000",
+		"traffic_monthly":  "20",
+	}
+}
+
+func TestResourceIPSeparateMac(t *testing.T) {
+	t.Run("unset keeps an existing MAC", func(t *testing.T) {
+		standIn, c := newIPStandIn(t, "00:50:56:00:00:01")
+		diff, _, err := applyIP(t, c, testIPState(true, false), map[string]interface{}{"ip": testIP})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff != nil && diff.Attributes["separate_mac"] != nil {
+			t.Errorf("separate_mac diff = %#v, want none", diff.Attributes["separate_mac"])
+		}
+		if got := standIn.recorded(); len(got) != 0 {
+			t.Errorf("requests = %v, want none", got)
+		}
+	})
+
+	t.Run("false refuses to remove a MAC it did not generate", func(t *testing.T) {
+		standIn, c := newIPStandIn(t, "00:50:56:00:00:01")
+		_, _, err := applyIP(t, c, testIPState(true, false), map[string]interface{}{"ip": testIP, "separate_mac": false})
+		if err == nil {
+			t.Fatal("apply succeeded, want an error")
+		}
+		if got := standIn.recorded(); len(got) != 0 {
+			t.Errorf("requests = %v, want none", got)
+		}
+	})
+
+	t.Run("false removes a generated MAC", func(t *testing.T) {
+		standIn, c := newIPStandIn(t, "00:50:56:00:00:01")
+		_, state, err := applyIP(t, c, testIPState(true, true), map[string]interface{}{"ip": testIP, "separate_mac": false})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := standIn.recorded(), []string{"DELETE /ip/" + testIP + "/mac"}; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("requests = %v, want %v", got, want)
+		}
+		if state.Attributes["separate_mac"] != "false" || state.Attributes["mac_generated"] != "false" || state.Attributes["mac"] != "" {
+			t.Errorf("state = %v, want the MAC gone", state.Attributes)
+		}
+	})
+
+	t.Run("create generates a MAC", func(t *testing.T) {
+		standIn, c := newIPStandIn(t, "")
+		_, state, err := applyIP(t, c, map[string]string{}, map[string]interface{}{"ip": testIP, "separate_mac": true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := standIn.recorded(), []string{"PUT /ip/" + testIP + "/mac"}; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("requests = %v, want %v", got, want)
+		}
+		if state.Attributes["mac_generated"] != "true" || state.Attributes["mac"] != "00:50:56:00:00:02" {
+			t.Errorf("state = %v, want a generated MAC", state.Attributes)
+		}
+	})
+
+	t.Run("create refuses false for an existing MAC", func(t *testing.T) {
+		standIn, c := newIPStandIn(t, "00:50:56:00:00:01")
+		_, state, err := applyIP(t, c, map[string]string{}, map[string]interface{}{"ip": testIP, "separate_mac": false})
+		if err == nil {
+			t.Fatal("apply succeeded, want an error")
+		}
+		if state != nil && state.ID != "" {
+			t.Errorf("ID = %q, want the IP left out of state", state.ID)
+		}
+		if got := standIn.recorded(); len(got) != 0 {
+			t.Errorf("requests = %v, want none", got)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// rdnsAttributePaths maps reverse DNS request parameters to resource attributes.
+var rdnsAttributePaths = attributePaths(map[string]string{
+	"ip":  "ip",
+	"ptr": "ptr",
+})
+
+func resourceRdns() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRdnsCreate,
+		ReadContext:   resourceRdnsRead,
+		UpdateContext: resourceRdnsUpdate,
+		DeleteContext: resourceRdnsDelete,
+		CustomizeDiff: resourceRdnsCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"ip": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				Description:      "IPv4 or IPv6 address of one of the account's servers or subnets",
+			},
+			"ptr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "PTR record",
+			},
+		},
+	}
+}
+
+// resourceRdnsCustomizeDiff refuses addresses which are not assigned to the
+// account, Robot would only report them as not found during apply.
+func resourceRdnsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown("ip") {
+		return nil
+	}
+
+	ip := net.ParseIP(d.Get("ip").(string))
+	if ip == nil {
+		return nil
+	}
+
+	c := meta.(*HetznerRobotClient)
+
+	servers, err := c.getServers(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list servers: %w", err)
+	}
+	if !serversOwnIP(servers, ip) {
+		return fmt.Errorf("ip: %s does not belong to any server or subnet of the account", ip)
+	}
+	return nil
+}
+
+func resourceRdnsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Get("ip").(string)
+	rdns, err := c.setRdns(ctx, ip, d.Get("ptr").(string))
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to create reverse DNS entry for %s", ip), rdnsAttributePaths)
+	}
+
+	d.SetId(rdns.IP)
+
+	return resourceRdnsRead(ctx, d, meta)
+}
+
+func resourceRdnsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	rdns, err := c.getRdns(ctx, ip)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "reverse DNS entry not found, removing from state", map[string]interface{}{
+				"ip": ip,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find reverse DNS entry for %s:\n\t %q", ip, err)
+	}
+
+	d.Set("ip", rdns.IP)
+	d.Set("ptr", rdns.PTR)
+
+	return nil
+}
+
+func resourceRdnsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	if _, err := c.setRdns(ctx, ip, d.Get("ptr").(string)); err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to update reverse DNS entry for %s", ip), rdnsAttributePaths)
+	}
+
+	return resourceRdnsRead(ctx, d, meta)
+}
+
+func resourceRdnsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	ip := d.Id()
+	if err := c.deleteRdns(ctx, ip); err != nil && !IsNotFound(err) {
+		return diag.Errorf("Unable to delete reverse DNS entry for %s:\n\t %q", ip, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+var testServers = []HetznerRobotServer{{
+	ServerIP:   "192.0.2.1",
+	ServerIPv6: "2001:db8:1234::",
+	IPs:        []string{"192.0.2.1", "192.0.2.10"},
+	Subnets: []HetznerRobotServerSubnet{
+		{IP: "198.51.100.0", Mask: "29"},
+		{IP: "203.0.113.0", Mask: "255.255.255.248"},
+		{IP: "2001:db8:5678::", Mask: "48"},
+	},
+}}
+
+func TestServersOwnIP(t *testing.T) {
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"192.0.2.1", true},
+		{"192.0.2.10", true},
+		{"192.0.2.11", false},
+		{"2001:db8:1234::1", true},
+		{"2001:db8:1234:0:ffff::1", true},
+		{"2001:db8:1234:1::1", false},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"203.0.113.5", true},
+		{"203.0.113.9", false},
+		{"2001:db8:5678:ab::1", true},
+		{"2001:db8:5679::1", false},
+	}
+	for _, tc := range cases {
+		if got := serversOwnIP(testServers, net.ParseIP(tc.ip)); got != tc.want {
+			t.Errorf("serversOwnIP(%s) = %v, want %v", tc.ip, got, tc.want)
+		}
+	}
+}
+
+func TestResourceRdnsCustomizeDiff(t *testing.T) {
+	servers := `[{"server":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","server_number":1,"ip":["192.0.2.1"],"subnet":[{"ip":"2001:db8:1234::","mask":"64"}]}}]`
+	cases := []struct {
+		ip      string
+		wantErr string
+	}{
+		{"192.0.2.1", ""},
+		{"2001:db8:1234::10", ""},
+		{"192.0.2.99", "ip: 192.0.2.99 does not belong to any server or subnet of the account"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.ip, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{"GET /server": servers})
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{"ip": tc.ip, "ptr": "node1.example.com"})
+			_, err := resourceRdns().Diff(context.Background(), nil, config, c)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("plan failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("plan error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceRdnsCreateAndUpdate(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /rdns/192.0.2.1": `{"rdns":{"ip":"192.0.2.1","ptr":"node1.example.com"}}`,
+		"GET /rdns/192.0.2.1":  `{"rdns":{"ip":"192.0.2.1","ptr":"node1.example.com"}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceRdns().Schema, map[string]interface{}{"ip": "192.0.2.1", "ptr": "node1.example.com"})
+
+	// an entry that already exists in Robot is taken over
+	if diags := resourceRdnsCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	if d.Id() != "192.0.2.1" || d.Get("ptr").(string) != "node1.example.com" {
+		t.Errorf("state = %v, want the entry read back", d.State())
+	}
+
+	d.Set("ptr", "web1.example.com")
+	if diags := resourceRdnsUpdate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("update failed: %v", diags)
+	}
+	robot.checkWrites(t,
+		"POST /rdns/192.0.2.1 ptr=node1.example.com",
+		"POST /rdns/192.0.2.1 ptr=web1.example.com",
+	)
+}
+
+func TestResourceRdnsDelete(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"DELETE /rdns/192.0.2.1": `{"error":{"status":404,"code":"RDNS_NOT_FOUND","message":"The IP address 192.0.2.1 has no reverse DNS entry"}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceRdns().Schema, map[string]interface{}{"ip": "192.0.2.1", "ptr": "node1.example.com"})
+	d.SetId("192.0.2.1")
+	if diags := resourceRdnsDelete(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("delete of a missing entry failed: %v", diags)
+	}
+	robot.checkWrites(t, "DELETE /rdns/192.0.2.1")
+}
+
+func TestGetRdnsEntries(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /rdns": `[{"rdns":{"ip":"192.0.2.1","ptr":"node1.example.com"}},{"rdns":{"ip":"2001:db8:1234::1","ptr":"node1.example.com"}}]`,
+	})
+	entries, err := c.getRdnsEntries(context.Background(), "192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].IP != "2001:db8:1234::1" {
+		t.Errorf("entries = %+v, want both entries", entries)
+	}
+	if got := robot.recorded()[0]; got != "GET /rdns?server_ip=192.0.2.1" {
+		t.Errorf("request = %q, want the server_ip filter", got)
+	}
+
+	// Robot answers a server without entries with RDNS_NOT_FOUND
+	robot.set("GET /rdns", `{"error":{"status":404,"code":"RDNS_NOT_FOUND","message":"no reverse DNS entries found"}}`)
+	entries, err = c.getRdnsEntries(context.Background(), "")
+	if err != nil || len(entries) != 0 {
+		t.Errorf("entries = %+v, %v, want none", entries, err)
+	}
+}
@@ -0,0 +1,265 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// serverAttributePaths maps server request parameters to resource attributes.
+var serverAttributePaths = attributePaths(map[string]string{
+	"cancellation_date": "cancellation_date",
+	"server_name":       "server_name",
+})
+
+var cancellationDatePattern = regexp.MustCompile(`^(now|\d{4}-\d{2}-\d{2})$`)
+
+// resourceServer adopts an existing dedicated server, it never orders one.
+func resourceServer() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerCreate,
+		ReadContext:   resourceServerRead,
+		UpdateContext: resourceServerUpdate,
+		DeleteContext: resourceServerDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Number of the existing server to manage",
+			},
+			"server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Server name",
+			},
+			"cancel_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "File a cancellation of the server on destroy instead of only removing it from state",
+			},
+			"cancellation_date": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "now",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(cancellationDatePattern,
+					"must be a date in the format YYYY-MM-DD or \"now\"")),
+				Description: "Cancellation date (YYYY-MM-DD or \"now\") used when cancel_on_destroy is set",
+			},
+			// read-only / computed
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server IP",
+			},
+			"server_ipv6": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server IPv6 Net",
+			},
+			"datacenter": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Data center",
+			},
+			"is_cancelled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Status of server cancellation",
+			},
+			"paid_until": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Paid until date",
+			},
+			"product": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server product name",
+			},
+			"ip_addresses": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Array of assigned single IP addresses",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"server_subnets": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Array of assigned subnets",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mask": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server status (\"ready\" or \"in process\")",
+			},
+			"traffic": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Free traffic quota, 'unlimited' in case of unlimited traffic",
+			},
+			"linked_storagebox": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Linked Storage Box ID",
+			},
+			"reset": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of reset system availability",
+			},
+			"rescue": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of Rescue System availability",
+			},
+			"vnc": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of VNC installation availability",
+			},
+			"windows": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of Windows installation availability",
+			},
+			"plesk": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of Plesk installation availability",
+			},
+			"cpanel": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of cPanel installation availability",
+			},
+			"wol": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of Wake On Lan availability",
+			},
+			"hot_swap": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag of Hot Swap availability",
+			},
+		},
+	}
+}
+
+func resourceServerImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serverNumber, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("invalid server number %q: %w", d.Id(), err)
+	}
+
+	d.Set("server_number", serverNumber)
+	d.Set("cancel_on_destroy", false)
+	d.Set("cancellation_date", "now")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceServerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	server, err := c.getServer(ctx, serverNumber)
+	if err != nil {
+		return diag.Errorf("Unable to adopt server %d:\n\t %q", serverNumber, err)
+	}
+
+	if name, ok := d.GetOk("server_name"); ok && name.(string) != server.ServerName {
+		if _, err := c.renameServer(ctx, serverNumber, name.(string)); err != nil {
+			return apiErrorDiagnostics(err, fmt.Sprintf("Unable to rename server %d", serverNumber), serverAttributePaths)
+		}
+	}
+
+	d.SetId(strconv.Itoa(serverNumber))
+
+	return resourceServerRead(ctx, d, meta)
+}
+
+func resourceServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	server, err := c.getServer(ctx, serverNumber)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "server not found, removing from state", map[string]interface{}{
+				"server_number": serverNumber,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find Server with number %d:\n\t %q", serverNumber, err)
+	}
+
+	for key, value := range flattenServer(server) {
+		d.Set(key, value)
+	}
+
+	return nil
+}
+
+func resourceServerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	if d.HasChange("server_name") {
+		if _, err := c.renameServer(ctx, serverNumber, d.Get("server_name").(string)); err != nil {
+			return apiErrorDiagnostics(err, fmt.Sprintf("Unable to rename server %d", serverNumber), serverAttributePaths)
+		}
+	}
+
+	return resourceServerRead(ctx, d, meta)
+}
+
+func resourceServerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	if !d.Get("cancel_on_destroy").(bool) {
+		tflog.Info(ctx, "removing server from state, the server itself is kept", map[string]interface{}{
+			"server_number": serverNumber,
+		})
+		return nil
+	}
+
+	cancellationDate := d.Get("cancellation_date").(string)
+	if _, err := c.cancelServer(ctx, serverNumber, cancellationDate, "", false); err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to cancel server %d", serverNumber), serverAttributePaths)
+	}
+
+	return nil
+}
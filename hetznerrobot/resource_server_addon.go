@@ -0,0 +1,189 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	serverAddonResourceIP     = "ip"
+	serverAddonResourceSubnet = "subnet"
+)
+
+// serverAddonAttributePaths maps addon order request parameters to resource attributes.
+var serverAddonAttributePaths = attributePaths(map[string]string{
+	"server_number": "server_number",
+	"product_id":    "product_id",
+	"reason":        "reason",
+	"gateway":       "gateway",
+})
+
+// resourceServerAddon orders an addon such as an additional IP or subnet for
+// a server. Like hetzner-robot_server_order, destroying it only removes it
+// from state.
+func resourceServerAddon() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerAddonCreate,
+		ReadContext:   resourceServerAddonRead,
+		DeleteContext: resourceServerOrderDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Number of the server to order the addon for",
+			},
+			"product_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Addon product ID, see the hetzner-robot_server_addons data source",
+			},
+			"reason": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Reason for the order, required by Robot for additional IPv4 addresses and subnets",
+			},
+			"gateway": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Gateway of the subnet, only for subnets routed to an additional IP",
+			},
+			"test": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Only validate the order, Robot does not process test orders",
+			},
+			// read-only / computed
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Transaction status (\"ready\", \"in process\" or \"cancelled\")",
+			},
+			"date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Date of the order",
+			},
+			"ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Allocated IP address, empty for subnets or until the order is ready",
+			},
+			"subnet": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Network address of the allocated subnet, empty for IPs or until the order is ready",
+			},
+			"subnet_mask": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Mask of the allocated subnet in CIDR notation",
+			},
+		},
+	}
+}
+
+func resourceServerAddonCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	order := HetznerRobotServerAddonOrder{
+		ServerNumber: d.Get("server_number").(int),
+		ProductID:    d.Get("product_id").(string),
+		Reason:       d.Get("reason").(string),
+		Gateway:      d.Get("gateway").(string),
+		Test:         d.Get("test").(bool),
+	}
+
+	transaction, err := c.orderServerAddon(ctx, order)
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to order addon %s for server %d", order.ProductID, order.ServerNumber), serverAddonAttributePaths)
+	}
+
+	d.SetId(transaction.ID)
+	setServerAddonTransaction(ctx, c, d, transaction)
+
+	if order.Test {
+		tflog.Info(ctx, "test addon order placed, it will not be processed", map[string]interface{}{
+			"transaction_id": transaction.ID,
+		})
+		return nil
+	}
+
+	result, diags := waitForOrderTransaction(ctx, "Addon order", d.Id(), d.Timeout(schema.TimeoutCreate), 10*time.Second,
+		"the allocated addresses are filled in by a later refresh",
+		func() (interface{}, string, error) {
+			transaction, err := c.getServerAddonTransaction(ctx, d.Id())
+			if err != nil {
+				return nil, "", err
+			}
+			return transaction, transaction.Status, nil
+		})
+	if result == nil {
+		return diags
+	}
+
+	setServerAddonTransaction(ctx, c, d, result.(*HetznerRobotServerAddonTransaction))
+
+	return nil
+}
+
+func resourceServerAddonRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	transactionID := d.Id()
+	transaction, err := c.getServerAddonTransaction(ctx, transactionID)
+	if err != nil {
+		// kept in state for the same reason as in readServerOrderTransaction
+		if IsNotFound(err) {
+			tflog.Info(ctx, "addon order transaction no longer available, keeping it in state", map[string]interface{}{
+				"transaction_id": transactionID,
+			})
+			return nil
+		}
+		return diag.Errorf("Unable to find addon order %s:\n\t %q", transactionID, err)
+	}
+
+	setServerAddonTransaction(ctx, c, d, transaction)
+
+	return nil
+}
+
+func setServerAddonTransaction(ctx context.Context, c *HetznerRobotClient, d *schema.ResourceData, transaction *HetznerRobotServerAddonTransaction) {
+	d.Set("status", transaction.Status)
+	d.Set("date", transaction.Date)
+
+	for _, resource := range transaction.Resources {
+		switch resource.Type {
+		case serverAddonResourceIP:
+			d.Set("ip", resource.ID)
+		case serverAddonResourceSubnet:
+			d.Set("subnet", resource.ID)
+			if d.Get("subnet_mask").(int) != 0 {
+				continue
+			}
+			subnet, err := c.getSubnet(ctx, resource.ID)
+			if err != nil {
+				tflog.Warn(ctx, "unable to look up mask of ordered subnet", map[string]interface{}{
+					"subnet": resource.ID,
+					"error":  err.Error(),
+				})
+				continue
+			}
+			d.Set("subnet_mask", subnet.Mask)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceServerAddonCreateTestOrder(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /order/server_addon/transaction": `{"transaction":{"id":"B20261017-1111111-2222222","date":"2026-10-17T12:00:00+02:00","status":"in process","product":{"id":"additional_ipv4","name":"Additional IPv4","type":"ip_ipv4","price":{"location":"FSN1","price":{"net":"1.7000","gross":"2.0230"},"price_setup":{"net":"19.0000","gross":"22.6100"}}},"resources":[]}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerAddon().Schema, map[string]interface{}{
+		"server_number": 321,
+		"product_id":    "additional_ipv4",
+		"reason":        "VPS",
+		"test":          true,
+	})
+	if diags := resourceServerAddonCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	robot.checkWrites(t, "POST /order/server_addon/transaction product_id=additional_ipv4&reason=VPS&server_number=321&test=true")
+	if d.Id() != "B20261017-1111111-2222222" || d.Get("status").(string) != transactionStatusInProcess || d.Get("ip").(string) != "" {
+		t.Errorf("state = %v, want the pending transaction", d.State())
+	}
+}
+
+func TestResourceServerAddonRead(t *testing.T) {
+	cases := []struct {
+		name       string
+		resources  string
+		wantIP     string
+		wantSubnet string
+		wantMask   int
+	}{
+		{"ip", `[{"type":"ip","id":"192.0.2.10"}]`, "192.0.2.10", "", 0},
+		{"subnet", `[{"type":"subnet","id":"2001:db8:1234::"}]`, "", "2001:db8:1234::", 64},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{
+				"GET /order/server_addon/transaction/B1": `{"transaction":{"id":"B1","date":"2026-10-17T12:00:00+02:00","status":"ready","product":{"id":"subnet_ipv4_29","name":"Additional subnet","type":"subnet"},"resources":` + tc.resources + `}}`,
+				"GET /subnet/2001:db8:1234::":            testSubnet,
+			})
+			d := schema.TestResourceDataRaw(t, resourceServerAddon().Schema, map[string]interface{}{"server_number": 321, "product_id": "subnet_ipv4_29"})
+			d.SetId("B1")
+			if diags := resourceServerAddonRead(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("read failed: %v", diags)
+			}
+			if d.Get("ip").(string) != tc.wantIP || d.Get("subnet").(string) != tc.wantSubnet || d.Get("subnet_mask").(int) != tc.wantMask {
+				t.Errorf("state = %v, want ip %q, subnet %q/%d", d.State(), tc.wantIP, tc.wantSubnet, tc.wantMask)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// serverCancellationAttributePaths maps cancellation request parameters to resource attributes.
+var serverCancellationAttributePaths = attributePaths(map[string]string{
+	"cancellation_date":   "cancellation_date",
+	"cancellation_reason": "reason",
+	"reserve_location":    "reserve_location",
+})
+
+// resourceServerCancellation files a cancellation for a server and revokes it on destroy.
+func resourceServerCancellation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerCancellationCreate,
+		ReadContext:   resourceServerCancellationRead,
+		DeleteContext: resourceServerCancellationDelete,
+		CustomizeDiff: resourceServerCancellationCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerCancellationImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Number of the server to cancel",
+			},
+			"cancellation_date": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "now",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(cancellationDatePattern,
+					"must be a date in the format YYYY-MM-DD or \"now\"")),
+				Description: "Cancellation date (YYYY-MM-DD or \"now\"), not before earliest_cancellation_date",
+			},
+			"reason": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Cancellation reason, one of the reasons offered by the hetzner-robot_server_cancellation data source",
+			},
+			"reserve_location": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Reserve the server location for a follow-up order",
+			},
+			// read-only / computed
+			"earliest_cancellation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Earliest possible cancellation date",
+			},
+			"cancelled_on": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Date the server is cancelled on",
+			},
+			"reserved": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the server location is reserved",
+			},
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server IP",
+			},
+			"server_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server name",
+			},
+		},
+	}
+}
+
+// resourceServerCancellationCustomizeDiff checks a new cancellation against the
+// dates and reasons Robot currently offers for the server.
+func resourceServerCancellationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown("server_number") || !d.NewValueKnown("cancellation_date") || !d.NewValueKnown("reason") {
+		return nil
+	}
+
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	cancellation, err := c.getServerCancellation(ctx, serverNumber)
+	if err != nil {
+		return fmt.Errorf("unable to get cancellation options of server %d: %w", serverNumber, err)
+	}
+	if cancellation.Cancelled {
+		return fmt.Errorf("server %d is already cancelled on %s, import the cancellation instead", serverNumber, cancellation.CancellationDate)
+	}
+
+	// dates are YYYY-MM-DD, so they compare lexically
+	cancellationDate := d.Get("cancellation_date").(string)
+	if cancellationDate != "now" && cancellationDate < cancellation.EarliestCancellationDate {
+		return fmt.Errorf("cancellation_date: %s is before the earliest cancellation date %s of server %d",
+			cancellationDate, cancellation.EarliestCancellationDate, serverNumber)
+	}
+
+	reason := d.Get("reason").(string)
+	if reason != "" && len(cancellation.Reasons) > 0 && !stringInSlice(reason, cancellation.Reasons) {
+		return fmt.Errorf("reason: %q is not one of %s", reason, strings.Join(cancellation.Reasons, ", "))
+	}
+
+	if d.Get("reserve_location").(bool) && !cancellation.ReservationPossible {
+		return fmt.Errorf("reserve_location: the location of server %d cannot be reserved", serverNumber)
+	}
+
+	return nil
+}
+
+func resourceServerCancellationImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("invalid server number %q: %w", d.Id(), err)
+	}
+
+	cancellation, err := c.getServerCancellation(ctx, serverNumber)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find cancellation of server %d: %w", serverNumber, err)
+	}
+	if !cancellation.Cancelled {
+		return nil, fmt.Errorf("server %d is not cancelled", serverNumber)
+	}
+
+	d.Set("server_number", serverNumber)
+	d.Set("cancellation_date", cancellation.CancellationDate)
+	d.Set("reason", cancellation.Reason)
+	d.Set("reserve_location", cancellation.Reserved)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceServerCancellationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	_, err := c.cancelServer(ctx, serverNumber,
+		d.Get("cancellation_date").(string),
+		d.Get("reason").(string),
+		d.Get("reserve_location").(bool))
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to cancel server %d", serverNumber), serverCancellationAttributePaths)
+	}
+
+	d.SetId(strconv.Itoa(serverNumber))
+
+	return resourceServerCancellationRead(ctx, d, meta)
+}
+
+func resourceServerCancellationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	cancellation, err := c.getServerCancellation(ctx, serverNumber)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "server not found, removing cancellation from state", map[string]interface{}{
+				"server_number": serverNumber,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find cancellation of server %d:\n\t %q", serverNumber, err)
+	}
+
+	if !cancellation.Cancelled {
+		tflog.Warn(ctx, "server cancellation was revoked, removing from state", map[string]interface{}{
+			"server_number": serverNumber,
+		})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("server_number", cancellation.ServerNumber)
+	// "now" resolves to the day the cancellation was filed, keep the configured value
+	if d.Get("cancellation_date").(string) != "now" {
+		d.Set("cancellation_date", cancellation.CancellationDate)
+	}
+	d.Set("reason", cancellation.Reason)
+	d.Set("earliest_cancellation_date", cancellation.EarliestCancellationDate)
+	d.Set("cancelled_on", cancellation.CancellationDate)
+	d.Set("reserved", cancellation.Reserved)
+	d.Set("server_ip", cancellation.ServerIP)
+	d.Set("server_name", cancellation.ServerName)
+
+	return nil
+}
+
+func resourceServerCancellationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	if err := c.revokeServerCancellation(ctx, serverNumber); err != nil && !IsNotFound(err) {
+		return diag.Errorf("Unable to revoke cancellation of server %d:\n\t %q", serverNumber, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package hetznerrobot
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Cancellation of server 1 before and after it was cancelled.
+const (
+	testCancellationOffered   = `{"cancellation":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","server_number":1,"server_name":"node1","earliest_cancellation_date":"2026-10-31","cancelled":false,"reservation_possible":true,"reserved":false,"cancellation_date":null,"cancellation_reason":["Upgrade to a new server","Dissatisfied with the hardware"]}}`
+	testCancellationCancelled = `{"cancellation":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","server_number":1,"server_name":"node1","earliest_cancellation_date":"2026-10-31","cancelled":true,"reservation_possible":false,"reserved":true,"cancellation_date":"2026-10-17","cancellation_reason":"Upgrade to a new server"}}`
+)
+
+func TestParseServerCancellation(t *testing.T) {
+	cases := []struct {
+		name        string
+		json        string
+		wantReasons []string
+		wantReason  string
+		wantErr     bool
+	}{
+		{"offered reasons", testCancellationOffered, []string{"Upgrade to a new server", "Dissatisfied with the hardware"}, "", false},
+		{"chosen reason", testCancellationCancelled, nil, "Upgrade to a new server", false},
+		{"no reason", `{"cancellation":{"server_number":1,"cancelled":true,"cancellation_reason":null}}`, nil, "", false},
+		{"unexpected reason", `{"cancellation":{"server_number":1,"cancellation_reason":{"text":"x"}}}`, nil, "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cancellation, err := parseServerCancellation([]byte(tc.json))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("parse succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cancellation.Reasons, tc.wantReasons) || cancellation.Reason != tc.wantReason {
+				t.Errorf("reasons = %q, reason = %q, want %q and %q", cancellation.Reasons, cancellation.Reason, tc.wantReasons, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestResourceServerCancellationCustomizeDiff(t *testing.T) {
+	cases := []struct {
+		name         string
+		cancellation string
+		config       map[string]interface{}
+		wantErr      string
+	}{
+		{"now", testCancellationOffered, map[string]interface{}{"server_number": 1}, ""},
+		{"offered reason and reservation", testCancellationOffered,
+			map[string]interface{}{"server_number": 1, "cancellation_date": "2026-10-31", "reason": "Upgrade to a new server", "reserve_location": true}, ""},
+		{"before the earliest date", testCancellationOffered,
+			map[string]interface{}{"server_number": 1, "cancellation_date": "2026-10-30"}, "cancellation_date: 2026-10-30 is before the earliest cancellation date 2026-10-31"},
+		{"reason not offered", testCancellationOffered,
+			map[string]interface{}{"server_number": 1, "reason": "Too expensive"}, `reason: "Too expensive" is not one of`},
+		{"already cancelled", testCancellationCancelled,
+			map[string]interface{}{"server_number": 1}, "server 1 is already cancelled on 2026-10-17"},
+		{"reservation not possible", strings.Replace(testCancellationOffered, `"reservation_possible":true`, `"reservation_possible":false`, 1),
+			map[string]interface{}{"server_number": 1, "reserve_location": true}, "reserve_location: "},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{"GET /server/1/cancellation": tc.cancellation})
+			_, err := resourceServerCancellation().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), c)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("plan failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("plan error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceServerCancellationCreate(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /server/1/cancellation": testCancellationCancelled,
+		"GET /server/1/cancellation":  testCancellationCancelled,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerCancellation().Schema, map[string]interface{}{
+		"server_number":    1,
+		"reason":           "Upgrade to a new server",
+		"reserve_location": true,
+	})
+	if diags := resourceServerCancellationCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	robot.checkWrites(t, "POST /server/1/cancellation cancellation_date=now&cancellation_reason=Upgrade+to+a+new+server&reserve_location=true")
+	if d.Id() != "1" || d.Get("cancellation_date").(string) != "now" || d.Get("cancelled_on").(string) != "2026-10-17" || !d.Get("reserved").(bool) {
+		t.Errorf("state = %v, want the cancellation read back with cancellation_date kept at now", d.State())
+	}
+}
+
+func TestResourceServerCancellationRead(t *testing.T) {
+	cases := []struct {
+		name         string
+		cancellation string
+		wantID       string
+	}{
+		{"cancelled", testCancellationCancelled, "1"},
+		{"revoked", testCancellationOffered, ""},
+		{"server gone", `{"error":{"status":404,"code":"SERVER_NOT_FOUND","message":"server not found"}}`, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{"GET /server/1/cancellation": tc.cancellation})
+			d := schema.TestResourceDataRaw(t, resourceServerCancellation().Schema, map[string]interface{}{"server_number": 1, "cancellation_date": "2026-10-17"})
+			d.SetId("1")
+			if diags := resourceServerCancellationRead(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("read failed: %v", diags)
+			}
+			if d.Id() != tc.wantID {
+				t.Errorf("ID = %q, want %q", d.Id(), tc.wantID)
+			}
+		})
+	}
+}
+
+func TestResourceServerCancellationDelete(t *testing.T) {
+	for _, response := range []string{
+		`{"cancellation":null}`,
+		`{"error":{"status":404,"code":"SERVER_NOT_FOUND","message":"server not found"}}`,
+	} {
+		robot, c := newRobotAPIStandIn(t, map[string]string{"DELETE /server/1/cancellation": response})
+		d := schema.TestResourceDataRaw(t, resourceServerCancellation().Schema, map[string]interface{}{"server_number": 1})
+		d.SetId("1")
+		if diags := resourceServerCancellationDelete(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("delete failed: %v", diags)
+		}
+		robot.checkWrites(t, "DELETE /server/1/cancellation")
+	}
+}
@@ -0,0 +1,86 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceServerMarketOrder buys a server from the server auction. Like
+// hetzner-robot_server_order, destroying it only removes it from state.
+func resourceServerMarketOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerMarketOrderCreate,
+		ReadContext:   resourceServerMarketOrderRead,
+		DeleteContext: resourceServerOrderDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(2 * time.Hour),
+		},
+		Schema: withServerOrderTransactionSchema(map[string]*schema.Schema{
+			"product_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Product ID, see the hetzner-robot_server_market_products data source",
+			},
+			"dist": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Distribution to preinstall, one of the product's dist values",
+			},
+			"arch": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Architecture of the distribution, deprecated by Robot",
+			},
+			"lang": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Language of the distribution, one of the product's lang values",
+			},
+			"addons": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "IDs of the addons to order with the server",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		}),
+	}
+}
+
+func resourceServerMarketOrderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	order := HetznerRobotServerOrder{
+		ProductID:      strconv.Itoa(d.Get("product_id").(int)),
+		Dist:           d.Get("dist").(string),
+		Arch:           d.Get("arch").(int),
+		Lang:           d.Get("lang").(string),
+		AuthorizedKeys: expandStringList(d.Get("authorized_keys").([]interface{})),
+		Addons:         expandStringList(d.Get("addons").([]interface{})),
+		Comment:        d.Get("comment").(string),
+		Test:           d.Get("test").(bool),
+	}
+
+	transaction, err := c.orderServerMarketProduct(ctx, order)
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to order server market product %s", order.ProductID), serverOrderAttributePaths)
+	}
+
+	return completeServerOrder(ctx, d, transaction, c.getServerMarketTransaction)
+}
+
+func resourceServerMarketOrderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	return readServerOrderTransaction(ctx, d, c.getServerMarketTransaction)
+}
@@ -0,0 +1,40 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceServerMarketOrderCreateTestOrder(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /order/server_market/transaction": `{"transaction":{"id":"B20261017-7654321","date":"2026-10-17T12:00:00+02:00","status":"in process","server_number":null,"server_ip":null,"comment":null}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerMarketOrder().Schema, map[string]interface{}{
+		"product_id": 2001,
+		"dist":       "Rescue system",
+		"test":       true,
+	})
+	if diags := resourceServerMarketOrderCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	robot.checkWrites(t, "POST /order/server_market/transaction dist=Rescue+system&product_id=2001&test=true")
+	if d.Id() != "B20261017-7654321" || d.Get("status").(string) != transactionStatusInProcess {
+		t.Errorf("state = %v, want the transaction", d.State())
+	}
+}
+
+func TestResourceServerMarketOrderRead(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /order/server_market/transaction/B20261017-7654321": `{"transaction":{"id":"B20261017-7654321","date":"2026-10-17T12:00:00+02:00","status":"ready","server_number":322,"server_ip":"192.0.2.2","comment":null}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerMarketOrder().Schema, map[string]interface{}{"product_id": 2001})
+	d.SetId("B20261017-7654321")
+	if diags := resourceServerMarketOrderRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("server_number").(int) != 322 || d.Get("server_ip").(string) != "192.0.2.2" {
+		t.Errorf("state = %v, want the provisioned server", d.State())
+	}
+}
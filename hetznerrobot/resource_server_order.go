@@ -0,0 +1,269 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// serverOrderAttributePaths maps order request parameters to resource attributes.
+var serverOrderAttributePaths = attributePaths(map[string]string{
+	"product_id":     "product_id",
+	"dist":           "dist",
+	"arch":           "arch",
+	"lang":           "lang",
+	"location":       "location",
+	"authorized_key": "authorized_keys",
+	"addon":          "addons",
+	"comment":        "comment",
+})
+
+// resourceServerOrder orders a new server. Robot cannot undo an order, so
+// destroying it only removes it from state; cancel the server with
+// hetzner-robot_server_cancellation instead.
+func resourceServerOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerOrderCreate,
+		ReadContext:   resourceServerOrderRead,
+		DeleteContext: resourceServerOrderDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(2 * time.Hour),
+		},
+		Schema: withServerOrderTransactionSchema(map[string]*schema.Schema{
+			"product_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Product ID, see the hetzner-robot_server_products data source",
+			},
+			"dist": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Distribution to preinstall, one of the product's dist values",
+			},
+			"arch": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Architecture of the distribution, deprecated by Robot",
+			},
+			"lang": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Language of the distribution, one of the product's lang values",
+			},
+			"location": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Location of the server, one of the product's locations",
+			},
+			"addons": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "IDs of the addons to order with the server",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		}),
+	}
+}
+
+// withServerOrderTransactionSchema adds the attributes shared by all server
+// orders to s.
+func withServerOrderTransactionSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for key, value := range map[string]*schema.Schema{
+		"authorized_keys": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Fingerprints of the SSH keys to authorize on the server",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"comment": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Order comment, orders with a comment are processed manually",
+		},
+		"test": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     false,
+			Description: "Only validate the order, Robot does not process test orders",
+		},
+		// read-only / computed
+		"status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Transaction status (\"ready\", \"in process\" or \"cancelled\")",
+		},
+		"date": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Date of the order",
+		},
+		"server_number": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of the ordered server once it is ready",
+		},
+		"server_ip": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Main IP of the ordered server once it is ready",
+		},
+	} {
+		s[key] = value
+	}
+	return s
+}
+
+func resourceServerOrderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	order := HetznerRobotServerOrder{
+		ProductID:      d.Get("product_id").(string),
+		Dist:           d.Get("dist").(string),
+		Arch:           d.Get("arch").(int),
+		Lang:           d.Get("lang").(string),
+		Location:       d.Get("location").(string),
+		AuthorizedKeys: expandStringList(d.Get("authorized_keys").([]interface{})),
+		Addons:         expandStringList(d.Get("addons").([]interface{})),
+		Comment:        d.Get("comment").(string),
+		Test:           d.Get("test").(bool),
+	}
+
+	transaction, err := c.orderServer(ctx, order)
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to order server %s", order.ProductID), serverOrderAttributePaths)
+	}
+
+	return completeServerOrder(ctx, d, transaction, c.getServerTransaction)
+}
+
+// completeServerOrder stores the transaction and waits for the server to be
+// ready, see waitForOrderTransaction.
+func completeServerOrder(ctx context.Context, d *schema.ResourceData, transaction *HetznerRobotServerTransaction,
+	getTransaction func(context.Context, string) (*HetznerRobotServerTransaction, error)) diag.Diagnostics {
+	d.SetId(transaction.ID)
+	setServerOrderTransaction(d, transaction)
+
+	if d.Get("test").(bool) {
+		tflog.Info(ctx, "test order placed, it will not be processed", map[string]interface{}{
+			"transaction_id": transaction.ID,
+		})
+		return nil
+	}
+
+	result, diags := waitForOrderTransaction(ctx, "Server order", d.Id(), d.Timeout(schema.TimeoutCreate), 30*time.Second,
+		"server_number and server_ip are filled in by a later refresh once Robot has provisioned the server",
+		func() (interface{}, string, error) {
+			transaction, err := getTransaction(ctx, d.Id())
+			if err != nil {
+				return nil, "", err
+			}
+			return transaction, transaction.Status, nil
+		})
+	if result == nil {
+		return diags
+	}
+
+	setServerOrderTransaction(d, result.(*HetznerRobotServerTransaction))
+
+	return nil
+}
+
+// waitForOrderTransaction polls refresh until the order transaction is ready
+// and returns it. Once the order is placed, failing would taint the resource
+// and order once more, so anything but a cancelled order is only reported as
+// a warning and a nil result; laterRefresh tells what a later refresh fills in.
+func waitForOrderTransaction(ctx context.Context, kind string, transactionID string, timeout time.Duration, pollInterval time.Duration,
+	laterRefresh string, refresh retry.StateRefreshFunc) (interface{}, diag.Diagnostics) {
+	stateConf := &retry.StateChangeConf{
+		Pending:    []string{transactionStatusInProcess},
+		Target:     []string{transactionStatusReady},
+		Refresh:    refresh,
+		Timeout:    timeout,
+		Delay:      pollInterval,
+		MinTimeout: pollInterval,
+	}
+
+	result, err := stateConf.WaitForStateContext(ctx)
+	if err == nil {
+		return result, nil
+	}
+
+	var timeoutErr *retry.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s %s is not ready yet", kind, transactionID),
+			Detail: fmt.Sprintf("The order is still %q after %s. It stays in state and %s.",
+				timeoutErr.LastState, timeout, laterRefresh),
+		}}
+	}
+	var stateErr *retry.UnexpectedStateError
+	if errors.As(err, &stateErr) && stateErr.State == transactionStatusCancelled {
+		return nil, diag.Errorf("%s %s was cancelled by Hetzner", kind, transactionID)
+	}
+	return nil, diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Unable to wait for %s %s", strings.ToLower(kind), transactionID),
+		Detail:   fmt.Sprintf("The order was placed and stays in state, a later refresh picks up its status:\n\t %q", err),
+	}}
+}
+
+func resourceServerOrderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	return readServerOrderTransaction(ctx, d, c.getServerTransaction)
+}
+
+// readServerOrderTransaction refreshes the transaction. Robot forgets
+// transactions after some time, a transaction which is gone is kept in state
+// as removing it would order the server again.
+func readServerOrderTransaction(ctx context.Context, d *schema.ResourceData,
+	getTransaction func(context.Context, string) (*HetznerRobotServerTransaction, error)) diag.Diagnostics {
+	transactionID := d.Id()
+	transaction, err := getTransaction(ctx, transactionID)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Info(ctx, "server order transaction no longer available, keeping it in state", map[string]interface{}{
+				"transaction_id": transactionID,
+			})
+			return nil
+		}
+		return diag.Errorf("Unable to find server order %s:\n\t %q", transactionID, err)
+	}
+
+	setServerOrderTransaction(d, transaction)
+
+	return nil
+}
+
+func resourceServerOrderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Warn(ctx, "removing server order from state, the server itself is kept", map[string]interface{}{
+		"transaction_id": d.Id(),
+		"server_number":  d.Get("server_number").(int),
+	})
+	return nil
+}
+
+func setServerOrderTransaction(d *schema.ResourceData, transaction *HetznerRobotServerTransaction) {
+	d.Set("status", transaction.Status)
+	d.Set("date", transaction.Date)
+	d.Set("server_number", transaction.ServerNumber)
+	d.Set("server_ip", transaction.ServerIP)
+}
@@ -0,0 +1,114 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceServerOrderCreateTestOrder(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /order/server/transaction": `{"transaction":{"id":"B20261017-1234567","date":"2026-10-17T12:00:00+02:00","status":"in process","server_number":null,"server_ip":null,"comment":null}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerOrder().Schema, map[string]interface{}{
+		"product_id":      "EX44",
+		"dist":            "Rescue system",
+		"location":        "FSN1",
+		"authorized_keys": []interface{}{"15:28:b0:03:95:f0:77:b3:10:56:15:6b:77:22:a5:bb"},
+		"addons":          []interface{}{"primary_ipv4"},
+		"test":            true,
+	})
+	// a test order is never processed, so it is not waited for
+	if diags := resourceServerOrderCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	robot.checkWrites(t, "POST /order/server/transaction "+
+		"addon%5B%5D=primary_ipv4&authorized_key%5B%5D=15%3A28%3Ab0%3A03%3A95%3Af0%3A77%3Ab3%3A10%3A56%3A15%3A6b%3A77%3A22%3Aa5%3Abb"+
+		"&dist=Rescue+system&location=FSN1&product_id=EX44&test=true")
+	if d.Id() != "B20261017-1234567" || d.Get("status").(string) != transactionStatusInProcess {
+		t.Errorf("state = %v, want the transaction", d.State())
+	}
+}
+
+func TestResourceServerOrderCreateInvalidLocation(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /order/server/transaction": `{"error":{"status":400,"code":"INVALID_INPUT","message":"invalid input","missing":null,"invalid":["location"]}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerOrder().Schema, map[string]interface{}{"product_id": "EX44", "location": "NBG1", "test": true})
+	diags := resourceServerOrderCreate(context.Background(), d, c)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("location")) {
+		t.Errorf("diagnostics = %v, want one at location", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID = %q, want none", d.Id())
+	}
+}
+
+func TestResourceServerOrderRead(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /order/server/transaction/B20261017-1234567": `{"transaction":{"id":"B20261017-1234567","date":"2026-10-17T12:00:00+02:00","status":"ready","server_number":321,"server_ip":"192.0.2.1","comment":null}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerOrder().Schema, map[string]interface{}{"product_id": "EX44"})
+	d.SetId("B20261017-1234567")
+	if diags := resourceServerOrderRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Get("server_number").(int) != 321 || d.Get("server_ip").(string) != "192.0.2.1" {
+		t.Errorf("state = %v, want the provisioned server", d.State())
+	}
+
+	// Robot forgets old transactions, the order must stay in state
+	robot.set("GET /order/server/transaction/B20261017-1234567", `{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`)
+	if diags := resourceServerOrderRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read of a forgotten transaction failed: %v", diags)
+	}
+	if d.Id() == "" || d.Get("server_number").(int) != 321 {
+		t.Errorf("state = %v, want the order kept", d.State())
+	}
+}
+
+func TestWaitForOrderTransaction(t *testing.T) {
+	statuses := func(list ...string) func() (interface{}, string, error) {
+		return func() (interface{}, string, error) {
+			status := list[0]
+			if len(list) > 1 {
+				list = list[1:]
+			}
+			return status, status, nil
+		}
+	}
+
+	cases := []struct {
+		name       string
+		refresh    func() (interface{}, string, error)
+		timeout    time.Duration
+		wantResult bool
+		wantSev    []diag.Severity
+	}{
+		{"ready", statuses(transactionStatusInProcess, transactionStatusReady), time.Minute, true, nil},
+		{"cancelled", statuses(transactionStatusInProcess, transactionStatusCancelled), time.Minute, false, []diag.Severity{diag.Error}},
+		{"timeout", statuses(transactionStatusInProcess), 50 * time.Millisecond, false, []diag.Severity{diag.Warning}},
+		{"refresh error", func() (interface{}, string, error) { return nil, "", errors.New("boom") }, time.Minute, false, []diag.Severity{diag.Warning}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, diags := waitForOrderTransaction(context.Background(), "Test order", "B1", tc.timeout, time.Millisecond, "nothing changes", tc.refresh)
+			if (result != nil) != tc.wantResult {
+				t.Errorf("result = %v, want result %v", result, tc.wantResult)
+			}
+			if len(diags) != len(tc.wantSev) {
+				t.Fatalf("got diagnostics %v, want severities %v", diags, tc.wantSev)
+			}
+			for i, d := range diags {
+				if d.Severity != tc.wantSev[i] {
+					t.Errorf("diagnostic %d severity = %v, want %v", i, d.Severity, tc.wantSev[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	portStateOpen   = "open"
+	portStateClosed = "closed"
+)
+
+// serverShutdownWait bounds how long a reset waits for the server to go down
+// before it waits for it to come back, a quick reboot may be missed entirely.
+const serverShutdownWait = 2 * time.Minute
+
+var serverResetTypes = []string{"sw", "hw", "man", "power", "power_long"}
+
+// serverResetAttributePaths maps reset request parameters to resource attributes.
+var serverResetAttributePaths = attributePaths(map[string]string{
+	"type": "type",
+})
+
+// resourceServerReset resets a server on create and whenever triggers change.
+// Destroying it only removes it from state.
+func resourceServerReset() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerResetCreate,
+		ReadContext:   resourceServerResetRead,
+		UpdateContext: resourceServerResetUpdate,
+		DeleteContext: resourceServerResetDelete,
+		CustomizeDiff: resourceServerResetCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Number of the server to reset",
+			},
+			"type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "sw",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(serverResetTypes, false)),
+				Description:      "Reset type, one of " + strings.Join(serverResetTypes, ", ") + "; must be supported by the server",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which trigger another reset when changed",
+			},
+			"wait_for_port": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 65535)),
+				Description:      "TCP port to wait for after the reset until the server accepts connections, 0 to not wait",
+			},
+			"wait_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Host to connect to when waiting, defaults to the server IP",
+			},
+			// read-only / computed
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server IP",
+			},
+			"reset_types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Reset types supported by the server",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// resourceServerResetCustomizeDiff checks the reset type against the types the
+// server advertises, so an unsupported reset fails at plan time.
+func resourceServerResetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("type") && !d.HasChange("triggers") {
+		return nil
+	}
+	if !d.NewValueKnown("server_number") || !d.NewValueKnown("type") {
+		return nil
+	}
+
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	options, err := c.getResetOptions(ctx, serverNumber)
+	if err != nil {
+		return fmt.Errorf("unable to get reset options of server %d: %w", serverNumber, err)
+	}
+
+	resetType := d.Get("type").(string)
+	if !stringInSlice(resetType, options.Types) {
+		return fmt.Errorf("type: server %d does not support reset type %q, supported are %s",
+			serverNumber, resetType, strings.Join(options.Types, ", "))
+	}
+	return nil
+}
+
+func resourceServerResetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	resetType := d.Get("type").(string)
+	reset, err := c.resetServer(ctx, serverNumber, resetType)
+	if err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to reset server %d", serverNumber), serverResetAttributePaths)
+	}
+
+	d.SetId(strconv.Itoa(serverNumber))
+	tflog.Info(ctx, "server reset", map[string]interface{}{
+		"server_number": serverNumber,
+		"type":          reset.Type,
+	})
+
+	if port := d.Get("wait_for_port").(int); port > 0 {
+		host := d.Get("wait_host").(string)
+		if host == "" {
+			host = reset.ServerIP
+		}
+		if diags := waitForServerPort(ctx, host, port, d.Timeout(schema.TimeoutCreate)); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceServerResetRead(ctx, d, meta)
+}
+
+func resourceServerResetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	options, err := c.getResetOptions(ctx, serverNumber)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "server not found, removing reset from state", map[string]interface{}{
+				"server_number": serverNumber,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find reset options of server %d:\n\t %q", serverNumber, err)
+	}
+
+	d.Set("server_ip", options.ServerIP)
+	d.Set("reset_types", options.Types)
+
+	return nil
+}
+
+// resourceServerResetUpdate only handles the wait settings, every other
+// change forces a new reset.
+func resourceServerResetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceServerResetRead(ctx, d, meta)
+}
+
+func resourceServerResetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// waitForServerPort waits for host to stop accepting connections on port and
+// then until it accepts them again.
+func waitForServerPort(ctx context.Context, host string, port int, timeout time.Duration) diag.Diagnostics {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	refresh := func() (interface{}, string, error) {
+		dialer := net.Dialer{Timeout: 5 * time.Second}
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return address, portStateClosed, nil
+		}
+		conn.Close()
+		return address, portStateOpen, nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	shutdownConf := &retry.StateChangeConf{
+		Pending:    []string{portStateOpen},
+		Target:     []string{portStateClosed},
+		Refresh:    refresh,
+		Timeout:    min(serverShutdownWait, timeout),
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := shutdownConf.WaitForStateContext(ctx); err != nil {
+		var timeoutErr *retry.TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			return diag.Errorf("Unable to wait for %s to go down:\n\t %q", address, err)
+		}
+		tflog.Info(ctx, "server did not go down, it may have rebooted already", map[string]interface{}{
+			"address": address,
+		})
+	}
+
+	startupConf := &retry.StateChangeConf{
+		Pending:    []string{portStateClosed},
+		Target:     []string{portStateOpen},
+		Refresh:    refresh,
+		Timeout:    time.Until(deadline),
+		MinTimeout: 5 * time.Second,
+	}
+	if _, err := startupConf.WaitForStateContext(ctx); err != nil {
+		var timeoutErr *retry.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Timed out waiting for %s to accept connections", address),
+				Detail: fmt.Sprintf("The server was reset but did not answer within %s. Manual resets and hardware "+
+					"checks can take longer; increase the create timeout of the resource if needed.", timeout),
+			}}
+		}
+		return diag.Errorf("Unable to wait for %s to accept connections:\n\t %q", address, err)
+	}
+
+	return nil
+}
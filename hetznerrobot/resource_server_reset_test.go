@@ -0,0 +1,85 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testResetOptions = `{"reset":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","server_number":1,"type":["sw","hw","man"],"operating_status":"not supported"}}`
+
+func TestResourceServerResetCustomizeDiff(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{"default type", map[string]interface{}{"server_number": 1}, ""},
+		{"supported type", map[string]interface{}{"server_number": 1, "type": "hw"}, ""},
+		{"unsupported type", map[string]interface{}{"server_number": 1, "type": "power"},
+			`type: server 1 does not support reset type "power", supported are sw, hw, man`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{"GET /reset/1": testResetOptions})
+			_, err := resourceServerReset().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), c)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("plan failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("plan error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceServerResetCreate(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /reset/1":  testResetOptions,
+		"POST /reset/1": `{"reset":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","type":"hw"}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerReset().Schema, map[string]interface{}{"server_number": 1, "type": "hw"})
+	if diags := resourceServerResetCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	robot.checkWrites(t, "POST /reset/1 type=hw")
+	if d.Id() != "1" || d.Get("server_ip").(string) != "192.0.2.1" || len(d.Get("reset_types").([]interface{})) != 3 {
+		t.Errorf("state = %v, want the reset options read back", d.State())
+	}
+}
+
+func TestResourceServerResetCreateRejected(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"POST /reset/1": `{"error":{"status":409,"code":"RESET_MANUAL_ACTIVE","message":"There is already a running manual reset"}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerReset().Schema, map[string]interface{}{"server_number": 1, "type": "man"})
+	diags := resourceServerResetCreate(context.Background(), d, c)
+	if !diags.HasError() {
+		t.Fatal("create succeeded, want an error")
+	}
+	if d.Id() != "" {
+		t.Errorf("ID = %q, want none for a rejected reset", d.Id())
+	}
+}
+
+func TestWaitForServerPortTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	diags := waitForServerPort(context.Background(), "127.0.0.1", port, 200*time.Millisecond)
+	if !diags.HasError() || !strings.HasPrefix(diags[0].Summary, "Timed out waiting") {
+		t.Errorf("wait = %v, want a timeout", diags)
+	}
+}
@@ -0,0 +1,110 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testServer = `{"server":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","server_number":1,"server_name":"node1","product":"AX41-NVMe","dc":"FSN1-DC14","traffic":"unlimited","status":"ready","cancelled":false,"paid_until":"2026-12-31","ip":["192.0.2.1"],"subnet":[{"ip":"2001:db8:1234::","mask":"64"}],"reset":true,"rescue":true,"vnc":true,"windows":false,"plesk":false,"cpanel":false,"wol":true,"hot_swap":false,"linked_storagebox":null}}`
+
+func TestResourceServerCreate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   []string
+	}{
+		{"adopt", map[string]interface{}{"server_number": 1}, nil},
+		{"same name", map[string]interface{}{"server_number": 1, "server_name": "node1"}, nil},
+		{"rename", map[string]interface{}{"server_number": 1, "server_name": "web-1"}, []string{"POST /server/1 server_name=web-1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			robot, c := newRobotAPIStandIn(t, map[string]string{
+				"GET /server/1":  testServer,
+				"POST /server/1": testServer,
+			})
+			d := schema.TestResourceDataRaw(t, resourceServer().Schema, tc.config)
+			if diags := resourceServerCreate(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("create failed: %v", diags)
+			}
+			robot.checkWrites(t, tc.want...)
+			if d.Id() != "1" || d.Get("server_ip").(string) != "192.0.2.1" || d.Get("datacenter").(string) != "FSN1-DC14" {
+				t.Errorf("state = %v, want server 1 read back", d.State())
+			}
+		})
+	}
+}
+
+func TestResourceServerCreateUnknownServer(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /server/2": `{"error":{"status":404,"code":"SERVER_NOT_FOUND","message":"server not found"}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{"server_number": 2, "server_name": "web-2"})
+	if diags := resourceServerCreate(context.Background(), d, c); !diags.HasError() {
+		t.Fatal("create succeeded, want an error")
+	}
+	robot.checkWrites(t)
+	if d.Id() != "" {
+		t.Errorf("ID = %q, want none", d.Id())
+	}
+}
+
+func TestResourceServerReadRemovesMissingServer(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /server/1": `{"error":{"status":404,"code":"SERVER_NOT_FOUND","message":"server not found"}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{"server_number": 1})
+	d.SetId("1")
+	if diags := resourceServerRead(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("read failed: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("ID = %q, want the server removed from state", d.Id())
+	}
+}
+
+func TestResourceServerDelete(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   []string
+	}{
+		{"keep", map[string]interface{}{"server_number": 1}, nil},
+		{"cancel now", map[string]interface{}{"server_number": 1, "cancel_on_destroy": true},
+			[]string{"POST /server/1/cancellation cancellation_date=now"}},
+		{"cancel at date", map[string]interface{}{"server_number": 1, "cancel_on_destroy": true, "cancellation_date": "2026-12-31"},
+			[]string{"POST /server/1/cancellation cancellation_date=2026-12-31"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			robot, c := newRobotAPIStandIn(t, map[string]string{
+				"POST /server/1/cancellation": `{"cancellation":{"server_ip":"192.0.2.1","server_number":1,"server_name":"node1","earliest_cancellation_date":"2026-10-31","cancelled":true,"reservation_possible":false,"reserved":false,"cancellation_date":"2026-12-31","cancellation_reason":null}}`,
+			})
+			d := schema.TestResourceDataRaw(t, resourceServer().Schema, tc.config)
+			d.SetId("1")
+			if diags := resourceServerDelete(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("delete failed: %v", diags)
+			}
+			robot.checkWrites(t, tc.want...)
+		})
+	}
+}
+
+func TestResourceServerImportState(t *testing.T) {
+	d := resourceServer().Data(nil)
+	d.SetId("1")
+	result, err := resourceServerImportState(context.Background(), d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || result[0].Get("server_number").(int) != 1 || result[0].Get("cancel_on_destroy").(bool) {
+		t.Errorf("imported %v, want server 1 kept on destroy", result[0].State())
+	}
+
+	d.SetId("node1")
+	if _, err := resourceServerImportState(context.Background(), d, nil); err == nil {
+		t.Error("import of a server name succeeded, want an error")
+	}
+}
@@ -0,0 +1,110 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceServerWol sends a Wake on LAN packet on create and whenever triggers
+// change. Destroying it only removes it from state.
+func resourceServerWol() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerWolCreate,
+		ReadContext:   resourceServerWolRead,
+		DeleteContext: resourceServerWolDelete,
+		CustomizeDiff: resourceServerWolCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Number of the server to wake up",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which trigger another Wake on LAN packet when changed",
+			},
+			// read-only / computed
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Server IP",
+			},
+		},
+	}
+}
+
+// resourceServerWolCustomizeDiff refuses servers without Wake on LAN support at plan time.
+func resourceServerWolCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("triggers") {
+		return nil
+	}
+	if !d.NewValueKnown("server_number") {
+		return nil
+	}
+
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	server, err := c.getServer(ctx, serverNumber)
+	if err != nil {
+		return fmt.Errorf("unable to find server %d: %w", serverNumber, err)
+	}
+	if !server.Wol {
+		return fmt.Errorf("server_number: server %d does not support Wake on LAN", serverNumber)
+	}
+	return nil
+}
+
+func resourceServerWolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber := d.Get("server_number").(int)
+	wol, err := c.sendWol(ctx, serverNumber)
+	if err != nil {
+		return diag.Errorf("Unable to send Wake on LAN packet to server %d:\n\t %q", serverNumber, err)
+	}
+
+	d.SetId(strconv.Itoa(serverNumber))
+	d.Set("server_ip", wol.ServerIP)
+
+	return resourceServerWolRead(ctx, d, meta)
+}
+
+func resourceServerWolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	serverNumber, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	server, err := c.getServer(ctx, serverNumber)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "server not found, removing Wake on LAN from state", map[string]interface{}{
+				"server_number": serverNumber,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find Server with number %d:\n\t %q", serverNumber, err)
+	}
+
+	d.Set("server_ip", server.ServerIP)
+
+	return nil
+}
+
+func resourceServerWolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
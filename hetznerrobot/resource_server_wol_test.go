@@ -0,0 +1,59 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceServerWolCustomizeDiff(t *testing.T) {
+	cases := []struct {
+		name    string
+		server  string
+		wantErr string
+	}{
+		{"supported", testServer, ""},
+		{"not supported", strings.Replace(testServer, `"wol":true`, `"wol":false`, 1), "server_number: server 1 does not support Wake on LAN"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c := newRobotAPIStandIn(t, map[string]string{"GET /server/1": tc.server})
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{"server_number": 1})
+			_, err := resourceServerWol().Diff(context.Background(), nil, config, c)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("plan failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("plan error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceServerWolCreate(t *testing.T) {
+	robot, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /server/1": testServer,
+		"POST /wol/1":   `{"wol":{"server_ip":"192.0.2.1","server_ipv6_net":"2001:db8:1234::","server_number":1}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceServerWol().Schema, map[string]interface{}{"server_number": 1})
+	if diags := resourceServerWolCreate(context.Background(), d, c); diags.HasError() {
+		t.Fatalf("create failed: %v", diags)
+	}
+	robot.checkWrites(t, "POST /wol/1")
+	if d.Id() != "1" || d.Get("server_ip").(string) != "192.0.2.1" {
+		t.Errorf("state = %v, want server 1", d.State())
+	}
+}
+
+func TestWolEndpointLimit(t *testing.T) {
+	if got := endpointLimit(endpointFamily("https://robot-ws.your-server.de", "https://robot-ws.your-server.de/wol/1")); got.requests != 500 || got.per != time.Hour {
+		t.Errorf("wol limit = %+v, want 500 per hour", got)
+	}
+}
@@ -3,11 +3,20 @@ package hetznerrobot
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"regexp"
 )
 
+// sshKeyAttributePaths maps SSH key request parameters to resource attributes.
+var sshKeyAttributePaths = attributePaths(map[string]string{
+	"data": "data",
+	"name": "name",
+})
+
 func resourceSshKey() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceSshKeyCreate,
@@ -56,7 +65,7 @@ func resourceSshKey() *schema.Resource {
 }
 
 func resourceSshKeyImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	keyFingerprint := d.Id()
 	match, err := regexp.Match(`^[0-9a-f]{2}(:[0-9a-f]{2}){15}$`, []byte(keyFingerprint))
@@ -83,14 +92,14 @@ func resourceSshKeyImportState(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceSshKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	name := d.Get("name").(string)
 	data := d.Get("data").(string)
 
 	key, err := c.createSshKey(ctx, name, data)
 	if err != nil {
-		return diag.Errorf("Unable to create SSH key %q:\n\t %q", name, err)
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to create SSH key %q", name), sshKeyAttributePaths)
 	}
 
 	d.Set("fingerprint", key.Fingerprint)
@@ -103,12 +112,19 @@ func resourceSshKeyCreate(ctx context.Context, d *schema.ResourceData, meta inte
 }
 
 func resourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	keyFingerprint := d.Id()
 
 	key, err := c.getSshKey(ctx, keyFingerprint)
 	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "SSH key not found, removing from state", map[string]interface{}{
+				"fingerprint": keyFingerprint,
+			})
+			d.SetId("")
+			return diag.Diagnostics{}
+		}
 		return diag.Errorf("Unable to find SSH key %q:\n\t %q", keyFingerprint, err)
 	}
 
@@ -123,14 +139,14 @@ func resourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func resourceSshKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	keyFingerprint := d.Id()
 	name := d.Get("name").(string)
 
 	key, err := c.updateSshKey(ctx, keyFingerprint, name)
 	if err != nil {
-		return diag.Errorf("Unable to update SSH key %q:\n\t %q", keyFingerprint, err)
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to update SSH key %q", keyFingerprint), sshKeyAttributePaths)
 	}
 
 	d.Set("name", key.Name)
@@ -139,12 +155,12 @@ func resourceSshKeyUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 }
 
 func resourceSshKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	keyFingerprint := d.Id()
 
 	err := c.deleteSshKey(ctx, keyFingerprint)
-	if err != nil {
+	if err != nil && !IsNotFound(err) {
 		return diag.Errorf("Unable to delete SSH key %q:\n\t %q", keyFingerprint, err)
 	}
 
@@ -0,0 +1,143 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceSubnet manages the traffic warning settings of a subnet. Destroying
+// it only removes it from state.
+func resourceSubnet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSubnetCreate,
+		ReadContext:   resourceSubnetRead,
+		UpdateContext: resourceSubnetUpdate,
+		DeleteContext: resourceSubnetDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: withTrafficWarningsSchema(map[string]*schema.Schema{
+			"ip": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPAddress),
+				Description:      "Network address of the subnet",
+			},
+			// read-only / computed
+			"mask": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Subnet mask in CIDR notation",
+			},
+			"gateway": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subnet gateway",
+			},
+			"server_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Main IP of the server the subnet is routed to",
+			},
+			"server_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of the server the subnet is routed to",
+			},
+			"failover": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the subnet is a failover subnet",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the subnet is locked",
+			},
+		}),
+	}
+}
+
+func resourceSubnetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	netIP := d.Get("ip").(string)
+	current, err := c.getSubnet(ctx, netIP)
+	if err != nil {
+		return diag.Errorf("Unable to find subnet %s:\n\t %q", netIP, err)
+	}
+
+	d.SetId(current.IP)
+
+	if diags := updateSubnetTrafficWarnings(ctx, c, d, current); diags.HasError() {
+		return diags
+	}
+
+	return resourceSubnetRead(ctx, d, meta)
+}
+
+func resourceSubnetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	netIP := d.Id()
+	subnet, err := c.getSubnet(ctx, netIP)
+	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "subnet not found, removing from state", map[string]interface{}{
+				"ip": netIP,
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("Unable to find subnet %s:\n\t %q", netIP, err)
+	}
+
+	for key, value := range flattenSubnet(subnet) {
+		d.Set(key, value)
+	}
+
+	return nil
+}
+
+func resourceSubnetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*HetznerRobotClient)
+
+	netIP := d.Id()
+	if d.HasChanges(trafficWarningsKeys...) {
+		current, err := c.getSubnet(ctx, netIP)
+		if err != nil {
+			return diag.Errorf("Unable to find subnet %s:\n\t %q", netIP, err)
+		}
+		if diags := updateSubnetTrafficWarnings(ctx, c, d, current); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceSubnetRead(ctx, d, meta)
+}
+
+func resourceSubnetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+// updateSubnetTrafficWarnings sends the configured traffic warning settings if
+// they differ from the current ones.
+func updateSubnetTrafficWarnings(ctx context.Context, c *HetznerRobotClient, d *schema.ResourceData, current *HetznerRobotSubnet) diag.Diagnostics {
+	warnings := expandTrafficWarnings(d, current.HetznerRobotTrafficWarnings)
+	if warnings == current.HetznerRobotTrafficWarnings {
+		return nil
+	}
+
+	if _, err := c.setSubnetTrafficWarnings(ctx, current.IP, warnings); err != nil {
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to update traffic warnings of subnet %s", current.IP), trafficWarningsAttributePaths)
+	}
+	return nil
+}
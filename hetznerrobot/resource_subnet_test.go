@@ -0,0 +1,58 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testSubnet = `{"subnet":{"ip":"2001:db8:1234::","mask":64,"gateway":"2001:db8:1234::1","server_ip":"192.0.2.1","server_number":1,"failover":false,"locked":false,"traffic_warnings":false,"traffic_hourly":200,"traffic_daily":2000,"traffic_monthly":20}}`
+
+func TestResourceSubnetCreate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config map[string]interface{}
+		want   []string
+	}{
+		{"settings left as they are", map[string]interface{}{}, nil},
+		{"same settings", map[string]interface{}{"traffic_hourly": 200, "traffic_warnings": false}, nil},
+		{"warnings enabled", map[string]interface{}{"traffic_warnings": true},
+			[]string{"POST /subnet/2001:db8:1234:: traffic_daily=2000&traffic_hourly=200&traffic_monthly=20&traffic_warnings=true"}},
+		{"limit changed", map[string]interface{}{"traffic_monthly": 50},
+			[]string{"POST /subnet/2001:db8:1234:: traffic_daily=2000&traffic_hourly=200&traffic_monthly=50&traffic_warnings=false"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			robot, c := newRobotAPIStandIn(t, map[string]string{
+				"GET /subnet/2001:db8:1234::":  testSubnet,
+				"POST /subnet/2001:db8:1234::": testSubnet,
+			})
+			config := map[string]interface{}{"ip": "2001:db8:1234::"}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+			d := schema.TestResourceDataRaw(t, resourceSubnet().Schema, config)
+			if diags := resourceSubnetCreate(context.Background(), d, c); diags.HasError() {
+				t.Fatalf("create failed: %v", diags)
+			}
+			robot.checkWrites(t, tc.want...)
+			if d.Id() != "2001:db8:1234::" || d.Get("mask").(int) != 64 || d.Get("gateway").(string) != "2001:db8:1234::1" {
+				t.Errorf("state = %v, want the subnet read back", d.State())
+			}
+		})
+	}
+}
+
+func TestResourceSubnetCreateInvalidLimit(t *testing.T) {
+	_, c := newRobotAPIStandIn(t, map[string]string{
+		"GET /subnet/2001:db8:1234::":  testSubnet,
+		"POST /subnet/2001:db8:1234::": `{"error":{"status":400,"code":"INVALID_INPUT","message":"invalid input","missing":null,"invalid":["traffic_daily"]}}`,
+	})
+	d := schema.TestResourceDataRaw(t, resourceSubnet().Schema, map[string]interface{}{"ip": "2001:db8:1234::", "traffic_daily": 10})
+	diags := resourceSubnetCreate(context.Background(), d, c)
+	if len(diags) != 1 || !diags[0].AttributePath.Equals(cty.GetAttrPath("traffic_daily")) {
+		t.Errorf("diagnostics = %v, want one at traffic_daily", diags)
+	}
+}
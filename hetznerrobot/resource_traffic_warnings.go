@@ -0,0 +1,79 @@
+package hetznerrobot
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var trafficWarningsKeys = []string{"traffic_warnings", "traffic_hourly", "traffic_daily", "traffic_monthly"}
+
+// trafficWarningsAttributePaths maps traffic warning request parameters to resource attributes.
+var trafficWarningsAttributePaths = attributePaths(map[string]string{
+	"traffic_warnings": "traffic_warnings",
+	"traffic_hourly":   "traffic_hourly",
+	"traffic_daily":    "traffic_daily",
+	"traffic_monthly":  "traffic_monthly",
+})
+
+// withTrafficWarningsSchema adds the traffic warning settings shared by IPs
+// and subnets to s. Unset settings keep their current value.
+func withTrafficWarningsSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for key, value := range map[string]*schema.Schema{
+		"traffic_warnings": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			Description: "Send traffic warnings when a limit is exceeded",
+		},
+		"traffic_hourly": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "Hourly traffic limit in MB",
+		},
+		"traffic_daily": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "Daily traffic limit in MB",
+		},
+		"traffic_monthly": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "Monthly traffic limit in GB",
+		},
+	} {
+		s[key] = value
+	}
+	return s
+}
+
+// expandTrafficWarnings returns the configured traffic warning settings,
+// falling back to current for unset ones.
+func expandTrafficWarnings(d *schema.ResourceData, current HetznerRobotTrafficWarnings) HetznerRobotTrafficWarnings {
+	warnings := current
+	if v, ok := d.GetOkExists("traffic_warnings"); ok {
+		warnings.TrafficWarnings = v.(bool)
+	}
+	if v, ok := d.GetOk("traffic_hourly"); ok {
+		warnings.TrafficHourly = v.(int)
+	}
+	if v, ok := d.GetOk("traffic_daily"); ok {
+		warnings.TrafficDaily = v.(int)
+	}
+	if v, ok := d.GetOk("traffic_monthly"); ok {
+		warnings.TrafficMonthly = v.(int)
+	}
+	return warnings
+}
+
+func flattenTrafficWarnings(d *schema.ResourceData, warnings HetznerRobotTrafficWarnings) {
+	d.Set("traffic_warnings", warnings.TrafficWarnings)
+	d.Set("traffic_hourly", warnings.TrafficHourly)
+	d.Set("traffic_daily", warnings.TrafficDaily)
+	d.Set("traffic_monthly", warnings.TrafficMonthly)
+}
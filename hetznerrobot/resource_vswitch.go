@@ -3,11 +3,20 @@ package hetznerrobot
 import (
 	"context"
 	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strconv"
 )
 
+// vSwitchAttributePaths maps vSwitch request parameters to resource attributes.
+var vSwitchAttributePaths = attributePaths(map[string]string{
+	"name":   "name",
+	"server": "servers",
+	"vlan":   "vlan",
+})
+
 func resourceVSwitch() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceVSwitchCreate,
@@ -111,12 +120,12 @@ func resourceVSwitch() *schema.Resource {
 	}
 }
 func resourceVSwitchImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	vSwitchID := d.Id()
 	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to find VSwitch with ID %d:\n\t %q", vSwitchID, err)
+		return nil, fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err)
 	}
 
 	d.Set("name", vSwitch.Name)
@@ -132,13 +141,13 @@ func resourceVSwitchImportState(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceVSwitchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	name := d.Get("name").(string)
 	vlan := d.Get("vlan").(int)
 	vSwitch, err := c.createVSwitch(ctx, name, vlan)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("Unable to create VSwitch :\n\t %q", err))
+		return apiErrorDiagnostics(err, "Unable to create VSwitch", vSwitchAttributePaths)
 	}
 
 	d.Set("is_cancelled", vSwitch.Cancelled)
@@ -154,11 +163,18 @@ func resourceVSwitchCreate(ctx context.Context, d *schema.ResourceData, meta int
 }
 
 func resourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	vSwitchID := d.Id()
 	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
 	if err != nil {
+		if IsNotFound(err) {
+			tflog.Warn(ctx, "VSwitch not found, removing from state", map[string]interface{}{
+				"id": vSwitchID,
+			})
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err))
 	}
 
@@ -176,14 +192,14 @@ func resourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta inter
 }
 
 func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	vSwitchID := d.Id()
 	name := d.Get("name").(string)
 	vlan := d.Get("vlan").(int)
 	err := c.updateVSwitch(ctx, vSwitchID, name, vlan)
 	if err != nil {
-		return diag.Errorf("Unable to update VSwitch:\n\t %q", err)
+		return apiErrorDiagnostics(err, fmt.Sprintf("Unable to update VSwitch %s", vSwitchID), vSwitchAttributePaths)
 	}
 
 	if d.HasChange("servers") {
@@ -207,7 +223,7 @@ func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 
 		if err := c.removeVSwitchServers(ctx, vSwitchID, serversToRemove); err != nil {
-			diag.Errorf("Unable to remove servers from VSwitch:\n\t %q", err)
+			return diag.Errorf("Unable to remove servers from VSwitch:\n\t %q", err)
 		}
 
 		ma := make(map[int]struct{}, len(oldServers))
@@ -225,7 +241,7 @@ func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 
 		if err := c.addVSwitchServers(ctx, vSwitchID, serversToAdd); err != nil {
-			diag.Errorf("Unable to add servers to VSwitch:\n\t %q", err)
+			return diag.Errorf("Unable to add servers to VSwitch:\n\t %q", err)
 		}
 	}
 
@@ -233,11 +249,11 @@ func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta int
 }
 
 func resourceVSwitchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*HetznerRobotClient)
 
 	vSwitchID := d.Id()
 	err := c.deleteVSwitch(ctx, vSwitchID)
-	if err != nil {
+	if err != nil && !IsNotFound(err) {
 		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err))
 	}
 
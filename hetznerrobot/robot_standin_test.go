@@ -0,0 +1,94 @@
+package hetznerrobot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// robotStandIn serves canned Robot webservice responses keyed by method and
+// path, e.g. "GET /server/1", and records the requests it receives. A route
+// without a response answers with Robot's NOT_FOUND error, a response that is
+// an error envelope is sent with the status it names.
+type robotStandIn struct {
+	mu       sync.Mutex
+	routes   map[string]string
+	requests []string
+}
+
+func newRobotAPIStandIn(t *testing.T, routes map[string]string) (*robotStandIn, *HetznerRobotClient) {
+	s := &robotStandIn{routes: map[string]string{}}
+	for route, body := range routes {
+		s.routes[route] = body
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unable to parse form of %s %s: %v", r.Method, r.URL, err)
+		}
+		route := r.Method + " " + r.URL.Path
+		request := route
+		if r.URL.RawQuery != "" {
+			request += "?" + r.URL.RawQuery
+		}
+		if len(r.PostForm) > 0 {
+			request += " " + r.PostForm.Encode()
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, request)
+		body, ok := s.routes[route]
+		s.mu.Unlock()
+
+		if !ok {
+			body = `{"error":{"status":404,"code":"NOT_FOUND","message":"Not found"}}`
+		}
+		if status := gjson.Get(body, "error.status"); status.Exists() {
+			w.WriteHeader(int(status.Int()))
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return s, NewHetznerRobotClient("user", "password", server.URL, 0, time.Second)
+}
+
+// set replaces the response of route, e.g. after a change was made.
+func (s *robotStandIn) set(route string, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[route] = body
+}
+
+// recorded returns the requests received so far.
+func (s *robotStandIn) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// writes returns the recorded requests other than GET.
+func (s *robotStandIn) writes() []string {
+	var writes []string
+	for _, request := range s.recorded() {
+		if !strings.HasPrefix(request, http.MethodGet+" ") {
+			writes = append(writes, request)
+		}
+	}
+	return writes
+}
+
+// checkWrites compares the recorded requests other than GET with want.
+func (s *robotStandIn) checkWrites(t *testing.T, want ...string) {
+	t.Helper()
+	got := s.writes()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests:\n\t%s\nwant:\n\t%s", strings.Join(got, "\n\t"), strings.Join(want, "\n\t"))
+	}
+}